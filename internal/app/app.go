@@ -1,9 +1,12 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"devhelper/internal/configcmd"
 	"devhelper/internal/converter"
 	"devhelper/internal/encoder"
 	"devhelper/internal/formatter"
@@ -11,6 +14,7 @@ import (
 	"devhelper/internal/hasher"
 	"devhelper/internal/httpclient"
 	"devhelper/internal/monitor"
+	"devhelper/pkg/config"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +30,8 @@ type VersionInfo struct {
 type App struct {
 	rootCmd     *cobra.Command
 	versionInfo VersionInfo
+	configErr   error // Ошибка загрузки конфигурации, о которой предупреждают команды
+	profileErr  error // Ошибка выбора профиля, при которой команды не выполняются
 }
 
 // New создает новый экземпляр приложения
@@ -49,12 +55,18 @@ func New(versionInfo VersionInfo) *App {
   * Генерация хэшей (MD5, SHA1, SHA256)
   * Простой HTTP-клиент для тестирования API
   * Мониторинг использования системных ресурсов`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			app.checkConfig(cmd)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			// Если нет подкоманды, показываем справку
 			cmd.Help()
 		},
 	}
 
+	// Профиль выбирается до разбора флагов (см. profileFromArgs), флаг объявлен для справки и проверки
+	app.rootCmd.PersistentFlags().String("profile", "", "Профиль настроек из конфигурации (по умолчанию из DEVHELPER_PROFILE или active_profile)")
+
 	// Добавляем флаг версии
 	app.rootCmd.AddCommand(&cobra.Command{
 		Use:   "version",
@@ -78,18 +90,109 @@ func (a *App) Run() error {
 	return a.rootCmd.Execute()
 }
 
+// loadedConfig результат загрузки конфигурации
+type loadedConfig struct {
+	cfg        *config.Config
+	warning    error // Ошибки загрузки, о которых предупреждают команды
+	profileErr error // Ошибка выбора профиля, при которой команды не выполняются
+}
+
+// loadConfig загружает конфигурацию пользователя, накладывает на нее файл проекта
+// .devhelper.yaml, применяет профиль и переопределяет настройки переменными окружения
+// DEVHELPER_*. Флаги команд используют результат как значения по умолчанию, поэтому
+// приоритет настроек: флаги > окружение > профиль > файл проекта > файл пользователя >
+// встроенные значения.
+// Профиль задается аргументом (флаг --profile), переменной DEVHELPER_PROFILE или active_profile.
+// Ошибки загрузки не прерывают работу и возвращаются для предупреждения, ошибка профиля - отдельно.
+func loadConfig(profile string) loadedConfig {
+	var errs []error
+
+	cfg := config.Default()
+	manager, err := config.LoadConfigManager()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("используются настройки по умолчанию, конфигурация не загружена:\n%w", err))
+	} else {
+		cfg = manager.Config
+	}
+
+	// Конфигурация проекта накладывается на пользовательскую
+	if path, err := config.ProjectPath(); err != nil {
+		errs = append(errs, err)
+	} else if path != "" {
+		if keys, err := cfg.MergeProjectFile(path); err != nil {
+			errs = append(errs, fmt.Errorf("конфигурация проекта не применена:\n%w", err))
+		} else if len(keys) > 0 {
+			errs = append(errs, fmt.Errorf("файл проекта %s изменяет настройки безопасности HTTP: %s", path, strings.Join(keys, ", ")))
+		}
+	}
+
+	if profile == "" {
+		profile = os.Getenv(config.ProfileEnv)
+	}
+	if profile == "" {
+		profile = cfg.ActiveProfile
+	}
+	var profileErr error
+	if profile != "" {
+		profileErr = cfg.ApplyProfile(profile)
+	}
+
+	if err := cfg.ApplyEnv(); err != nil {
+		errs = append(errs, err)
+	}
+	return loadedConfig{cfg: cfg, warning: errors.Join(errs...), profileErr: profileErr}
+}
+
+// profileFromArgs возвращает значение флага --profile из аргументов командной строки.
+// Конфигурация загружается до разбора флагов, так как задает их значения по умолчанию.
+func profileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--profile="); ok {
+			return value
+		}
+		if arg == "--profile" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// checkConfig предупреждает об ошибке загрузки конфигурации и завершает работу при ошибке
+// выбора профиля. Команды config сообщают об ошибках файла сами.
+func (a *App) checkConfig(cmd *cobra.Command) {
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Name() == "config" && c.Parent() == a.rootCmd {
+			return
+		}
+	}
+	if a.configErr != nil {
+		fmt.Fprintf(os.Stderr, "Предупреждение: %s\n", a.configErr)
+	}
+	if a.profileErr != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %s\n", a.profileErr)
+		os.Exit(1)
+	}
+}
+
 // registerCommands регистрирует все команды приложения
 func (a *App) registerCommands() {
+	loaded := loadConfig(profileFromArgs(os.Args[1:]))
+	cfg := loaded.cfg
+	a.configErr, a.profileErr = loaded.warning, loaded.profileErr
+
 	// Форматирование
-	formatterCmd := formatter.NewCommand()
+	formatterCmd := formatter.NewCommand(cfg)
 	a.rootCmd.AddCommand(formatterCmd)
 
 	// Конвертация
-	converterCmd := converter.NewCommand()
+	converterCmd := converter.NewCommand(cfg)
 	a.rootCmd.AddCommand(converterCmd)
 
 	// Генерация тестовых данных
-	generatorCmd := generator.NewCommand()
+	generatorCmd := generator.NewCommand(cfg)
 	a.rootCmd.AddCommand(generatorCmd)
 
 	// Кодирование/декодирование
@@ -101,13 +204,21 @@ func (a *App) registerCommands() {
 	a.rootCmd.AddCommand(hasherCmd)
 
 	// HTTP-клиент
-	httpCmd := httpclient.NewCommand()
+	httpCmd := httpclient.NewCommand(cfg)
 	a.rootCmd.AddCommand(httpCmd)
 
+	// Коллекции HTTP-запросов
+	reqCmd := httpclient.NewCollectionCommand(cfg)
+	a.rootCmd.AddCommand(reqCmd)
+
 	// Мониторинг ресурсов
-	monitorCmd := monitor.NewCommand()
+	monitorCmd := monitor.NewCommand(cfg)
 	a.rootCmd.AddCommand(monitorCmd)
 
+	// Настройки
+	configCmd := configcmd.NewCommand()
+	a.rootCmd.AddCommand(configCmd)
+
 	// Добавляем команду для завершения shell
 	a.rootCmd.AddCommand(&cobra.Command{
 		Use:   "completion [bash|zsh|fish|powershell]",
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -8,6 +10,8 @@ import (
 )
 
 func TestNew(t *testing.T) {
+	t.Setenv("DEVHELPER_CONFIG", filepath.Join(t.TempDir(), "c.yaml"))
+
 	versionInfo := VersionInfo{
 		Version:   "1.0.0-test",
 		BuildTime: "2025-01-01T12:00:00Z",
@@ -23,6 +27,8 @@ func TestNew(t *testing.T) {
 }
 
 func TestRun(t *testing.T) {
+	t.Setenv("DEVHELPER_CONFIG", filepath.Join(t.TempDir(), "c.yaml"))
+
 	app := New(VersionInfo{})
 
 	// Создаем собственный Command для тестирования
@@ -39,3 +45,133 @@ func TestRun(t *testing.T) {
 	// Это непрямой тест, который проверяет, что функциональность вообще работает
 	assert.NotNil(t, app.rootCmd)
 }
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("DEVHELPER_GENERAL_DEFAULT_INDENT", "4")
+
+	loaded := loadConfig("")
+	assert.NoError(t, loaded.profileErr)
+	assert.NoError(t, loaded.warning)
+
+	// Значение из окружения переопределяет файл конфигурации
+	assert.Equal(t, 4, loaded.cfg.General.DefaultIndent)
+	assert.Equal(t, "alphanumeric", loaded.cfg.Generator.DefaultCharset)
+
+	// Настройки становятся значениями флагов по умолчанию
+	app := New(VersionInfo{})
+	formatCmd, _, err := app.rootCmd.Find([]string{"format"})
+	assert.NoError(t, err)
+	assert.Equal(t, "4", formatCmd.Flags().Lookup("indent").DefValue)
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("DEVHELPER_CONFIG", path)
+	assert.NoError(t, os.WriteFile(path, []byte("general:\n  default_indnet: 4\n"), 0644))
+
+	// Ошибка в файле не прерывает работу: используются значения по умолчанию
+	loaded := loadConfig("")
+	assert.ErrorContains(t, loaded.warning, path+":2: general.default_indnet")
+	assert.Equal(t, 2, loaded.cfg.General.DefaultIndent)
+}
+
+func TestLoadConfig_Profile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("DEVHELPER_CONFIG", path)
+	t.Setenv("DEVHELPER_PROFILE", "")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+active_profile: staging
+profiles:
+  staging:
+    general:
+      default_indent: 4
+  ci:
+    general:
+      default_indent: 8
+      color_enabled: false
+`), 0644))
+
+	// Активный профиль из файла
+	loaded := loadConfig("")
+	assert.NoError(t, loaded.warning)
+	assert.NoError(t, loaded.profileErr)
+	assert.Equal(t, 4, loaded.cfg.General.DefaultIndent)
+
+	// Переменная окружения переопределяет active_profile, флаг - переменную
+	t.Setenv("DEVHELPER_PROFILE", "ci")
+	assert.Equal(t, 8, loadConfig("").cfg.General.DefaultIndent)
+	assert.Equal(t, 4, loadConfig("staging").cfg.General.DefaultIndent)
+
+	// Переменные настроек переопределяют профиль
+	t.Setenv("DEVHELPER_GENERAL_DEFAULT_INDENT", "6")
+	loaded = loadConfig("ci")
+	assert.Equal(t, 6, loaded.cfg.General.DefaultIndent)
+	assert.False(t, loaded.cfg.General.ColorEnabled)
+
+	assert.ErrorContains(t, loadConfig("prod").profileErr, "профиль prod не найден")
+}
+
+func TestLoadConfig_Project(t *testing.T) {
+	userPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("DEVHELPER_CONFIG", userPath)
+	t.Setenv("DEVHELPER_PROFILE", "")
+	assert.NoError(t, os.WriteFile(userPath, []byte(`
+general:
+  default_indent: 4
+http:
+  default_user_agent: user-agent
+`), 0644))
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "cmd", "api")
+	assert.NoError(t, os.MkdirAll(nested, 0755))
+	t.Chdir(nested)
+
+	projectPath := filepath.Join(root, ".devhelper.yaml")
+	assert.NoError(t, os.WriteFile(projectPath, []byte(`
+http:
+  default_headers:
+    - "X-Team: api"
+active_profile: ci
+profiles:
+  ci:
+    general:
+      default_indent: 8
+`), 0644))
+
+	// Файл проекта накладывается на пользовательский, профиль из проекта применяется.
+	// О заголовках из файла проекта выводится предупреждение
+	loaded := loadConfig("")
+	assert.EqualError(t, loaded.warning, "файл проекта "+projectPath+" изменяет настройки безопасности HTTP: http.default_headers")
+	assert.NoError(t, loaded.profileErr)
+	assert.Equal(t, []string{"X-Team: api"}, loaded.cfg.HTTP.DefaultHeaders)
+	assert.Equal(t, "user-agent", loaded.cfg.HTTP.DefaultUserAgent)
+	assert.Equal(t, 8, loaded.cfg.General.DefaultIndent)
+
+	// Ошибка в файле проекта не отменяет пользовательскую конфигурацию
+	assert.NoError(t, os.WriteFile(projectPath, []byte("htp:\n  timeout: 5s\n"), 0644))
+	loaded = loadConfig("")
+	assert.ErrorContains(t, loaded.warning, projectPath+":1: htp")
+	assert.Equal(t, 4, loaded.cfg.General.DefaultIndent)
+}
+
+func TestProfileFromArgs(t *testing.T) {
+	assert.Equal(t, "ci", profileFromArgs([]string{"http", "--profile", "ci", "https://example.com"}))
+	assert.Equal(t, "ci", profileFromArgs([]string{"--profile=ci", "format", "json"}))
+	assert.Equal(t, "", profileFromArgs([]string{"http", "--", "--profile", "ci"}))
+	assert.Equal(t, "", profileFromArgs([]string{"http", "--profile"}))
+}
+
+func TestNew_DoesNotCreateConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "devhelper", "config.yaml")
+	t.Setenv("DEVHELPER_CONFIG", configPath)
+
+	// Загрузка настроек при запуске любой команды не создает конфигурационный файл
+	New(VersionInfo{})
+	assert.NoFileExists(t, configPath)
+	assert.NoDirExists(t, filepath.Dir(configPath))
+}
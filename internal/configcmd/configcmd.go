@@ -0,0 +1,352 @@
+package configcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"devhelper/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand создает новую команду просмотра и изменения настроек
+func NewCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Просмотр и изменение настроек",
+		Long: `Просмотр и изменение настроек в конфигурационном файле.
+Ключи задаются в виде раздел.ключ, например http.timeout или monitor.default_interval.
+Команды показывают значения из файла пользователя без учета файла проекта .devhelper.yaml
+и переменных окружения DEVHELPER_*.`,
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "Показать значение настройки",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			manager := loadManager()
+			value, err := manager.Config.Get(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(value)
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set [key] [value...]",
+		Short: "Изменить значение настройки",
+		Long: `Изменить значение настройки и сохранить конфигурационный файл.
+Длительности задаются в формате 30s, 5m, 24h, логические значения - true или false.
+Элементы списков передаются отдельными аргументами, например
+devhelper config set http.default_headers "Accept: application/json" "X-Debug: 1".`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			manager := loadManager()
+			if err := manager.Config.SetValues(args[0], args[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			if err := manager.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			value, _ := manager.Config.Get(args[0])
+			fmt.Printf("%s = %s\n", args[0], value)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Показать все настройки",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			printList(os.Stdout, loadManager().Config)
+		},
+	}
+
+	var all bool
+	pathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "Показать путь к конфигурационному файлу",
+		Long: `Показать путь к конфигурационному файлу пользователя.
+С флагом --all выводятся все применяемые файлы в порядке наложения: файл пользователя,
+затем файл проекта .devhelper.yaml, найденный в текущем каталоге или выше.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(configPath())
+			if !all {
+				return
+			}
+			if path := projectPath(); path != "" {
+				fmt.Println(path)
+			}
+		},
+	}
+	pathCmd.Flags().BoolVar(&all, "all", false, "Показать все применяемые файлы, включая файл проекта")
+
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Сбросить настройки к значениям по умолчанию",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Файл перезаписывается без загрузки, поэтому сброс работает и для поврежденного файла
+			manager := &config.ConfigManager{Config: config.Default(), ConfigPath: configPath()}
+			// Отсутствующий файл создается с настройками по умолчанию
+			if _, err := os.Stat(manager.ConfigPath); err != nil {
+				if err := manager.Save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+					os.Exit(1)
+				}
+			}
+			if err := manager.ResetToDefault(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Настройки сброшены к значениям по умолчанию: %s\n", manager.ConfigPath)
+		},
+	}
+
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Открыть конфигурационный файл в редакторе",
+		Long: `Открыть конфигурационный файл в редакторе из переменной окружения $VISUAL или $EDITOR.
+После закрытия редактора файл проверяется на корректность.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Файл открывается без загрузки, чтобы в редакторе можно было исправить ошибки
+			manager := &config.ConfigManager{Config: config.Default(), ConfigPath: configPath()}
+			// Отсутствующий файл создается с настройками по умолчанию
+			if _, err := os.Stat(manager.ConfigPath); err != nil {
+				if err := manager.Save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+					os.Exit(1)
+				}
+			}
+			if err := edit(manager.ConfigPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			if err := manager.Load(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка в конфигурационном файле: %s\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Проверить конфигурационный файл",
+		Long: `Проверить конфигурационный файл: неизвестные ключи, типы и допустимые значения настроек.
+Если в текущем каталоге или выше есть файл проекта .devhelper.yaml, он проверяется вместе
+с файлом пользователя. Ошибки выводятся с номерами строк, при ошибках команда завершается с кодом 1.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			manager := &config.ConfigManager{Config: config.Default(), ConfigPath: configPath()}
+			// Отсутствующий файл создается с настройками по умолчанию
+			if _, err := os.Stat(manager.ConfigPath); err != nil {
+				if err := manager.Save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+					os.Exit(1)
+				}
+			}
+			failed := false
+			if err := manager.Load(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+			} else {
+				fmt.Printf("Конфигурация корректна: %s\n", manager.ConfigPath)
+			}
+
+			// Файл проекта проверяется с учетом настроек пользователя, на которые он накладывается
+			if path := projectPath(); path != "" {
+				if err := manager.Config.MergeFile(path); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					failed = true
+				} else {
+					fmt.Printf("Конфигурация корректна: %s\n", path)
+				}
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+
+	configCmd.AddCommand(getCmd, setCmd, listCmd, pathCmd, resetCmd, editCmd, validateCmd, newProfileCommand())
+
+	return configCmd
+}
+
+// newProfileCommand создает команду управления профилями
+func newProfileCommand() *cobra.Command {
+	var none bool
+
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Управление профилями настроек",
+		Long: `Профили задаются в разделе profiles конфигурационного файла и переопределяют
+отдельные настройки основных разделов. Профиль выбирается флагом --profile,
+переменной окружения DEVHELPER_PROFILE или ключом active_profile (в порядке приоритета).`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Показать профили",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg := loadManager().Config
+			flag, _ := cmd.Flags().GetString("profile")
+			printProfiles(os.Stdout, cfg, selectedProfile(cfg, flag))
+		},
+	}
+
+	useCmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "Выбрать профиль по умолчанию",
+		Long: `Сохранить профиль в ключе active_profile конфигурационного файла.
+С флагом --none профиль по умолчанию не применяется.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if none {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			manager := loadManager()
+			name := ""
+			if !none {
+				name = args[0]
+				if _, ok := manager.Config.Profiles[name]; !ok {
+					fmt.Fprintf(os.Stderr, "Ошибка: профиль %s не найден в %s\n", name, manager.ConfigPath)
+					os.Exit(1)
+				}
+			}
+
+			manager.Config.ActiveProfile = name
+			if err := manager.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+
+			if name == "" {
+				fmt.Println("Профиль по умолчанию не используется")
+			} else {
+				fmt.Printf("Профиль по умолчанию: %s\n", name)
+			}
+			if env := os.Getenv(config.ProfileEnv); env != "" && env != name {
+				fmt.Fprintf(os.Stderr, "Предупреждение: переменная %s=%s переопределяет профиль по умолчанию\n", config.ProfileEnv, env)
+			}
+		},
+	}
+	useCmd.Flags().BoolVar(&none, "none", false, "Не применять профиль по умолчанию")
+
+	profileCmd.AddCommand(listCmd, useCmd)
+
+	return profileCmd
+}
+
+// selectedProfile возвращает профиль, выбранный флагом, переменной окружения или active_profile
+func selectedProfile(cfg *config.Config, flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(config.ProfileEnv); env != "" {
+		return env
+	}
+	return cfg.ActiveProfile
+}
+
+// printProfiles выводит профили и переопределяемые ими настройки, отмечая выбранный профиль
+func printProfiles(w io.Writer, cfg *config.Config, selected string) {
+	if len(cfg.Profiles) == 0 {
+		fmt.Fprintln(w, "Профили не заданы")
+		return
+	}
+	for _, name := range cfg.ProfileNames() {
+		mark := " "
+		if name == selected {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", mark, name, strings.Join(cfg.Profiles[name].Keys(), ", "))
+	}
+}
+
+// loadManager загружает конфигурационный файл, завершая работу при ошибке
+func loadManager() *config.ConfigManager {
+	manager, err := config.LoadConfigManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфигурации: %s\n", err)
+		os.Exit(1)
+	}
+	return manager
+}
+
+// configPath возвращает путь к конфигурационному файлу, завершая работу при ошибке
+func configPath() string {
+	path, err := config.Path()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// projectPath возвращает путь к файлу проекта или пустую строку, завершая работу при ошибке
+func projectPath() string {
+	path, err := config.ProjectPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// printList выводит все настройки в виде ключ = значение
+func printList(w io.Writer, cfg *config.Config) {
+	for _, key := range cfg.Keys() {
+		value, _ := cfg.Get(key)
+		// Элементы списков выводятся с новой строки с отступом под значением
+		value = strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", len(key)+3))
+		fmt.Fprintf(w, "%s = %s\n", key, value)
+	}
+}
+
+// editorCommand возвращает редактор из $VISUAL или $EDITOR, иначе редактор по умолчанию для ОС
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// edit открывает файл в редакторе и ждет его закрытия.
+// Редактор может быть задан с аргументами, например "code --wait".
+func edit(path string) error {
+	editor := editorCommand()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("не удалось запустить редактор %s: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package configcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"devhelper/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintList(t *testing.T) {
+	cfg := config.Default()
+	require.NoError(t, cfg.Set("http.timeout", "5s"))
+	require.NoError(t, cfg.Set("http.default_headers", "Accept: a, b\nX-Debug: 1"))
+
+	var buf bytes.Buffer
+	printList(&buf, cfg)
+
+	output := buf.String()
+	assert.Contains(t, output, "general.color_enabled = true\n")
+	assert.Contains(t, output, "http.timeout = 5s\n")
+	assert.Contains(t, output, "http.default_headers = Accept: a, b\n                       X-Debug: 1\n")
+	assert.Contains(t, output, "monitor.default_display = dashboard\n")
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	assert.Equal(t, "nano", editorCommand())
+
+	// $VISUAL имеет приоритет над $EDITOR
+	t.Setenv("VISUAL", "code --wait")
+	assert.Equal(t, "code --wait", editorCommand())
+}
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("редактор запускается через sh")
+	}
+
+	// Редактор с аргументами получает путь к файлу последним аргументом
+	path := filepath.Join(t.TempDir(), "config with spaces.yaml")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "cp /dev/null")
+	require.NoError(t, os.WriteFile(path, []byte("general: {}\n"), 0644))
+
+	require.NoError(t, edit(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	t.Setenv("EDITOR", "false")
+	assert.Error(t, edit(path))
+}
+
+func TestPrintProfiles(t *testing.T) {
+	cfg := config.Default()
+
+	var buf bytes.Buffer
+	printProfiles(&buf, cfg, "")
+	assert.Equal(t, "Профили не заданы\n", buf.String())
+
+	cfg.ActiveProfile = "ci"
+	cfg.Profiles = map[string]config.Profile{
+		"ci":      {"general": {"color_enabled": false}},
+		"staging": {"http": {"timeout": "10s", "default_headers": []any{"X-Env: staging"}}},
+	}
+
+	t.Setenv("DEVHELPER_PROFILE", "")
+	assert.Equal(t, "ci", selectedProfile(cfg, ""))
+	assert.Equal(t, "staging", selectedProfile(cfg, "staging"))
+	t.Setenv("DEVHELPER_PROFILE", "staging")
+	assert.Equal(t, "staging", selectedProfile(cfg, ""))
+
+	buf.Reset()
+	printProfiles(&buf, cfg, "staging")
+	assert.Equal(t, "  ci: general.color_enabled\n* staging: http.default_headers, http.timeout\n", buf.String())
+}
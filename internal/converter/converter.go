@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"devhelper/pkg/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -28,7 +29,7 @@ func NewConverter(reader io.Reader, writer io.Writer) *Converter {
 }
 
 // NewCommand создает новую команду конвертации
-func NewCommand() *cobra.Command {
+func NewCommand(cfg *config.Config) *cobra.Command {
 	var (
 		outputFile string
 		indent     int
@@ -101,7 +102,7 @@ func NewCommand() *cobra.Command {
 	}
 
 	convertCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Выходной файл (по умолчанию: stdout)")
-	convertCmd.Flags().IntVar(&indent, "indent", 2, "Размер отступа для форматирования")
+	convertCmd.Flags().IntVar(&indent, "indent", cfg.General.DefaultIndent, "Размер отступа для форматирования")
 
 	return convertCmd
 }
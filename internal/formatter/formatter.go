@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"devhelper/pkg/config"
 	_ "github.com/alecthomas/chroma"
 	"github.com/alecthomas/chroma/formatters"
 	"github.com/alecthomas/chroma/lexers"
@@ -32,7 +33,7 @@ func NewFormatter(reader io.Reader, writer io.Writer) *Formatter {
 }
 
 // NewCommand создает новую команду форматирования
-func NewCommand() *cobra.Command {
+func NewCommand(cfg *config.Config) *cobra.Command {
 	var (
 		noColor bool
 		indent  int
@@ -80,8 +81,8 @@ func NewCommand() *cobra.Command {
 		},
 	}
 
-	formatCmd.Flags().BoolVar(&noColor, "no-color", false, "Отключить подсветку синтаксиса")
-	formatCmd.Flags().IntVar(&indent, "indent", 2, "Размер отступа для форматирования")
+	formatCmd.Flags().BoolVar(&noColor, "no-color", !cfg.General.ColorEnabled, "Отключить подсветку синтаксиса")
+	formatCmd.Flags().IntVar(&indent, "indent", cfg.General.DefaultIndent, "Размер отступа для форматирования")
 
 	return formatCmd
 }
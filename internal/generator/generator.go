@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"devhelper/pkg/config"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
@@ -28,7 +29,7 @@ func NewGenerator(writer io.Writer) *Generator {
 }
 
 // NewCommand создает новую команду генерации тестовых данных
-func NewCommand() *cobra.Command {
+func NewCommand(cfg *config.Config) *cobra.Command {
 	genCmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Генерация тестовых данных",
@@ -62,7 +63,7 @@ func NewCommand() *cobra.Command {
 			}
 		},
 	}
-	uuidCmd.Flags().StringP("format", "f", "string", "Формат вывода (string, json, csv)")
+	uuidCmd.Flags().StringP("format", "f", cfg.Generator.DefaultOutputType, "Формат вывода (string, json, csv)")
 	uuidCmd.Flags().BoolP("upper", "u", false, "Преобразовать UUID в верхний регистр")
 
 	// Подкоманда для генерации строк
@@ -102,8 +103,8 @@ func NewCommand() *cobra.Command {
 			}
 		},
 	}
-	stringCmd.Flags().StringP("charset", "c", "alphanumeric", "Набор символов (alphanumeric, alpha, numeric, ascii, hex)")
-	stringCmd.Flags().StringP("format", "f", "string", "Формат вывода (string, json, csv)")
+	stringCmd.Flags().StringP("charset", "c", cfg.Generator.DefaultCharset, "Набор символов (alphanumeric, alpha, numeric, ascii, hex)")
+	stringCmd.Flags().StringP("format", "f", cfg.Generator.DefaultOutputType, "Формат вывода (string, json, csv)")
 
 	// Подкоманда для генерации чисел
 	numberCmd := &cobra.Command{
@@ -151,7 +152,7 @@ func NewCommand() *cobra.Command {
 			}
 		},
 	}
-	numberCmd.Flags().StringP("format", "f", "string", "Формат вывода (string, json, csv)")
+	numberCmd.Flags().StringP("format", "f", cfg.Generator.DefaultOutputType, "Формат вывода (string, json, csv)")
 	numberCmd.Flags().Bool("float", false, "Генерировать дробные числа вместо целых")
 
 	// Подкоманда для генерации дат
@@ -192,8 +193,8 @@ func NewCommand() *cobra.Command {
 			}
 		},
 	}
-	dateCmd.Flags().StringP("format", "f", "string", "Формат вывода (string, json, csv)")
-	dateCmd.Flags().StringP("date-format", "d", "2006-01-02", "Формат даты (Go time format)")
+	dateCmd.Flags().StringP("format", "f", cfg.Generator.DefaultOutputType, "Формат вывода (string, json, csv)")
+	dateCmd.Flags().StringP("date-format", "d", cfg.Generator.DefaultDateFormat, "Формат даты (Go time format)")
 
 	// Добавляем подкоманды
 	genCmd.AddCommand(uuidCmd)
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"devhelper/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -374,3 +375,13 @@ func TestGenerator_GenerateDate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_ConfigValues(t *testing.T) {
+	// Все наборы символов и форматы, допустимые в конфигурации, поддерживаются генератором
+	for _, charset := range config.Charsets {
+		for _, format := range config.OutputTypes {
+			var out bytes.Buffer
+			assert.NoError(t, NewGenerator(&out).GenerateString(8, 1, charset, format), "%s/%s", charset, format)
+		}
+	}
+}
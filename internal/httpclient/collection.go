@@ -0,0 +1,242 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Collection представляет коллекцию сохраненных HTTP-запросов
+type Collection struct {
+	// Name название коллекции
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Variables значения переменных по умолчанию, переопределяются файлами окружения
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	// Requests запросы в порядке добавления
+	Requests []SavedRequest `json:"requests" yaml:"requests"`
+}
+
+// SavedRequest представляет именованный запрос коллекции.
+// В URL, заголовках и теле можно использовать переменные вида {{name}}.
+type SavedRequest struct {
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Method      string            `json:"method,omitempty" yaml:"method,omitempty"`
+	URL         string            `json:"url" yaml:"url"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body        string            `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// Follow и MaxRedirects политика перенаправлений запроса, по умолчанию - из конфигурации
+	Follow       *bool `json:"follow,omitempty" yaml:"follow,omitempty"`
+	MaxRedirects *int  `json:"max_redirects,omitempty" yaml:"max_redirects,omitempty"`
+	// TLS параметры TLS-соединения запроса, пути к файлам отсчитываются от каталога коллекции
+	TLS *TLSOptions `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// variablePattern шаблон переменной {{name}}, пробелы внутри скобок допускаются
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// fileFormat определяет формат файла коллекции или окружения по расширению
+func fileFormat(path string) (string, error) {
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	default:
+		return "", fmt.Errorf("неподдерживаемое расширение файла: %s (ожидается .yaml, .yml или .json)", ext)
+	}
+}
+
+// decodeFile разбирает файл YAML или JSON, неизвестные поля считаются ошибкой
+func decodeFile(path string, v any) error {
+	format, err := fileFormat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(v)
+	default:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(v)
+		// Пустой файл YAML не является ошибкой
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadCollection загружает коллекцию из файла YAML или JSON и проверяет запросы
+func LoadCollection(path string) (*Collection, error) {
+	collection := &Collection{}
+	if err := decodeFile(path, collection); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки коллекции: %w", err)
+	}
+	if err := collection.validate(); err != nil {
+		return nil, fmt.Errorf("ошибка в коллекции %s: %w", path, err)
+	}
+	return collection, nil
+}
+
+// validate проверяет, что у запросов заданы уникальные имена и URL
+func (c *Collection) validate() error {
+	seen := make(map[string]bool)
+	for i, request := range c.Requests {
+		if request.Name == "" {
+			return fmt.Errorf("у запроса %d не задано имя", i+1)
+		}
+		if seen[request.Name] {
+			return fmt.Errorf("запрос %s встречается несколько раз", request.Name)
+		}
+		seen[request.Name] = true
+		if request.URL == "" {
+			return fmt.Errorf("у запроса %s не задан URL", request.Name)
+		}
+	}
+	return nil
+}
+
+// Save сохраняет коллекцию в файл, формат определяется по расширению
+func (c *Collection) Save(path string) error {
+	format, err := fileFormat(path)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "json":
+		data, err = json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации JSON коллекции: %w", err)
+		}
+		data = append(data, '\n')
+	default:
+		data, err = yaml.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("ошибка сериализации YAML коллекции: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл коллекции: %w", err)
+	}
+	return nil
+}
+
+// Request возвращает запрос коллекции по имени
+func (c *Collection) Request(name string) (SavedRequest, error) {
+	names := make([]string, 0, len(c.Requests))
+	for _, request := range c.Requests {
+		if request.Name == name {
+			return request, nil
+		}
+		names = append(names, request.Name)
+	}
+	if len(names) == 0 {
+		return SavedRequest{}, fmt.Errorf("запрос %s не найден, коллекция пуста", name)
+	}
+	return SavedRequest{}, fmt.Errorf("запрос %s не найден (доступны: %s)", name, strings.Join(names, ", "))
+}
+
+// Put добавляет запрос в коллекцию или заменяет запрос с тем же именем.
+// Возвращает true, если запрос был заменен.
+func (c *Collection) Put(request SavedRequest) bool {
+	for i := range c.Requests {
+		if c.Requests[i].Name == request.Name {
+			c.Requests[i] = request
+			return true
+		}
+	}
+	c.Requests = append(c.Requests, request)
+	return false
+}
+
+// LoadEnvironment загружает переменные из файла окружения YAML или JSON
+// вида имя: значение
+func LoadEnvironment(path string) (map[string]string, error) {
+	var values map[string]any
+	if err := decodeFile(path, &values); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки окружения: %w", err)
+	}
+
+	variables := make(map[string]string, len(values))
+	for name, value := range values {
+		switch value.(type) {
+		case map[string]any, []any:
+			return nil, fmt.Errorf("ошибка загрузки окружения: %s: значение %s должно быть строкой или числом", path, name)
+		case nil:
+			variables[name] = ""
+		default:
+			variables[name] = fmt.Sprint(value)
+		}
+	}
+	return variables, nil
+}
+
+// method возвращает HTTP-метод запроса, по умолчанию GET
+func (r SavedRequest) method() string {
+	if r.Method == "" {
+		return "GET"
+	}
+	return strings.ToUpper(r.Method)
+}
+
+// Resolve подставляет значения переменных в URL, заголовки и тело запроса.
+// Если для переменной нет значения, возвращается ошибка со списком таких переменных.
+func (r SavedRequest) Resolve(variables map[string]string) (SavedRequest, error) {
+	missing := make(map[string]bool)
+	replace := func(s string) string {
+		return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := variablePattern.FindStringSubmatch(match)[1]
+			value, ok := variables[name]
+			if !ok {
+				missing[name] = true
+				return match
+			}
+			return value
+		})
+	}
+
+	resolved := r
+	resolved.URL = replace(r.URL)
+	resolved.Body = replace(r.Body)
+	resolved.Headers = make(map[string]string, len(r.Headers))
+	for key, value := range r.Headers {
+		resolved.Headers[replace(key)] = replace(value)
+	}
+	resolved.Method = r.method()
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return SavedRequest{}, fmt.Errorf("не заданы значения переменных: %s", strings.Join(names, ", "))
+	}
+	return resolved, nil
+}
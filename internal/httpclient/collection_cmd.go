@@ -0,0 +1,269 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devhelper/pkg/config"
+	"github.com/briandowns/spinner"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// NewCollectionCommand создает команду работы с коллекциями HTTP-запросов.
+// Команды коллекций не вложены в http [url], чтобы их имена не совпадали с URL.
+func NewCollectionCommand(cfg *config.Config) *cobra.Command {
+	reqCmd := &cobra.Command{
+		Use:   "req",
+		Short: "Коллекции HTTP-запросов",
+		Long: `Хранение именованных HTTP-запросов в файлах коллекций YAML или JSON и их выполнение.
+Коллекции удобно держать в репозитории вместе с файлами окружений.`,
+	}
+	reqCmd.AddCommand(newRunCommand(cfg), newListCommand(), newSaveCommand(cfg))
+	return reqCmd
+}
+
+// newRunCommand создает команду выполнения запроса из коллекции
+func newRunCommand(cfg *config.Config) *cobra.Command {
+	var (
+		envFiles []string
+		vars     []string
+		flags    requestFlags
+	)
+
+	runCmd := &cobra.Command{
+		Use:   "run [collection] [name]",
+		Short: "Выполнить запрос из коллекции",
+		Long: `Выполнить именованный запрос из файла коллекции YAML или JSON.
+Переменные {{name}} в URL, заголовках и теле берутся из раздела variables коллекции,
+затем из файлов окружения --env (по порядку) и флагов --var, каждый следующий
+источник переопределяет предыдущие. Заголовки по умолчанию и User-Agent берутся из
+конфигурации, заголовки запроса коллекции переопределяют их. Параметры перенаправлений
+и TLS запроса коллекции заменяют значения из конфигурации, явно заданные флаги - их.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := flags.validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			collection, err := LoadCollection(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			saved, err := collection.Request(args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+
+			variables, err := collectionVariables(collection, envFiles, vars)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			request, err := saved.Resolve(variables)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка в запросе %s: %s\n", saved.Name, err)
+				os.Exit(1)
+			}
+
+			options := savedClientOptions(cmd, flags.clientOptions(), request, filepath.Dir(args[0]), os.Stderr)
+			client, err := NewHTTPClientWithOptions(options)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка настройки HTTP-клиента: %s\n", err)
+				os.Exit(1)
+			}
+			headers := requestHeaders(cfg, request.Headers)
+
+			// Отображаем спиннер во время запроса
+			s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+			s.Suffix = fmt.Sprintf(" Выполнение запроса %s...", saved.Name)
+			s.Start()
+
+			response, err := client.SendRequest(request.Method, request.URL, headers, []byte(request.Body), "", "", options.TLS.Insecure)
+			s.Stop()
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка при выполнении запроса: %s\n", err)
+				os.Exit(1)
+			}
+
+			if flags.verbose {
+				printRequest(request.Method, request.URL, headers, []byte(request.Body), response)
+			}
+			printStatus(response)
+			printResponseBody(response.Body, response.Headers["Content-Type"], !flags.noColor)
+			reportTiming(flags.timing, response)
+		},
+	}
+
+	runCmd.Flags().StringArrayVarP(&envFiles, "env", "e", nil, "Файл окружения YAML или JSON с переменными (можно указать несколько)")
+	runCmd.Flags().StringArrayVar(&vars, "var", nil, "Значение переменной (формат: 'имя=значение')")
+	bindRequestFlags(runCmd, cfg, &flags)
+
+	return runCmd
+}
+
+// newListCommand создает команду просмотра запросов коллекции
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [collection]",
+		Short: "Показать запросы коллекции",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			collection, err := LoadCollection(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			printCollection(os.Stdout, collection)
+		},
+	}
+}
+
+// newSaveCommand создает команду сохранения запроса в коллекцию
+func newSaveCommand(cfg *config.Config) *cobra.Command {
+	var (
+		method      string
+		headers     []string
+		data        string
+		dataFile    string
+		description string
+		flags       requestFlags
+	)
+
+	saveCmd := &cobra.Command{
+		Use:   "save [collection] [name] [url]",
+		Short: "Сохранить запрос в коллекцию",
+		Long: `Сохранить запрос в файл коллекции YAML или JSON. Если файла нет, он создается,
+запрос с тем же именем заменяется. В URL, заголовках и теле можно использовать
+переменные {{name}}, значения которых подставляются при выполнении командой run.
+Явно заданные флаги перенаправлений и TLS сохраняются вместе с запросом.
+Файл коллекции перезаписывается, поэтому комментарии в нем не сохраняются.`,
+		Args: cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, name, url := args[0], args[1], args[2]
+
+			collection := &Collection{}
+			if _, err := os.Stat(path); err == nil {
+				if collection, err = LoadCollection(path); err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+					os.Exit(1)
+				}
+			}
+
+			body := data
+			if dataFile != "" {
+				content, err := os.ReadFile(dataFile)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка чтения файла данных: %s\n", err)
+					os.Exit(1)
+				}
+				body = string(content)
+			}
+
+			request := SavedRequest{
+				Name:        name,
+				Description: description,
+				Method:      strings.ToUpper(method),
+				URL:         url,
+				Body:        body,
+			}
+			if len(headers) > 0 {
+				request.Headers = parseHeaders(headers)
+			}
+			if err := flags.saveTo(cmd, &request, filepath.Dir(path)); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+
+			replaced := collection.Put(request)
+			if err := collection.Save(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			if replaced {
+				fmt.Printf("Запрос %s обновлен в %s\n", name, path)
+			} else {
+				fmt.Printf("Запрос %s сохранен в %s\n", name, path)
+			}
+		},
+	}
+
+	saveCmd.Flags().StringVarP(&method, "method", "X", "GET", "HTTP-метод (GET, POST, PUT, DELETE и т.д.)")
+	saveCmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "HTTP-заголовки (формат: 'Ключ: Значение')")
+	saveCmd.Flags().StringVarP(&data, "data", "d", "", "Данные для отправки в теле запроса")
+	saveCmd.Flags().StringVarP(&dataFile, "data-file", "f", "", "Файл с данными для отправки в теле запроса")
+	saveCmd.Flags().StringVar(&description, "description", "", "Описание запроса")
+	bindRedirectFlags(saveCmd, cfg, &flags)
+	bindTLSFlags(saveCmd, cfg, &flags.tls)
+
+	return saveCmd
+}
+
+// collectionVariables собирает значения переменных: переменные коллекции, затем
+// файлы окружения и значения имя=значение, каждый следующий источник переопределяет предыдущие
+func collectionVariables(collection *Collection, envFiles, vars []string) (map[string]string, error) {
+	variables := make(map[string]string, len(collection.Variables))
+	for name, value := range collection.Variables {
+		variables[name] = value
+	}
+
+	for _, path := range envFiles {
+		env, err := LoadEnvironment(path)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range env {
+			variables[name] = value
+		}
+	}
+
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("неверный формат переменной %q, ожидается имя=значение", v)
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
+
+// requestHeaders собирает заголовки запроса коллекции: заголовки и User-Agent
+// из конфигурации, затем переопределяющие их заголовки запроса
+func requestHeaders(cfg *config.Config, headers map[string]string) map[string]string {
+	result := parseHeaders(cfg.HTTP.DefaultHeaders)
+	if _, ok := result["User-Agent"]; !ok && cfg.HTTP.DefaultUserAgent != "" {
+		result["User-Agent"] = cfg.HTTP.DefaultUserAgent
+	}
+	for key, value := range headers {
+		result[http.CanonicalHeaderKey(key)] = value
+	}
+	return result
+}
+
+// printCollection выводит запросы коллекции в виде таблицы
+func printCollection(w io.Writer, collection *Collection) {
+	if collection.Name != "" {
+		fmt.Fprintln(w, collection.Name)
+	}
+	if len(collection.Requests) == 0 {
+		fmt.Fprintln(w, "Коллекция не содержит запросов")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Имя", "Метод", "URL", "Описание"})
+	for _, request := range collection.Requests {
+		t.AppendRow(table.Row{request.Name, request.method(), request.URL, request.Description})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -0,0 +1,242 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"devhelper/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const collectionYAML = `
+name: Users API
+variables:
+  base_url: http://localhost:8080
+  user_id: "1"
+requests:
+  - name: list-users
+    url: "{{base_url}}/users"
+  - name: create-user
+    description: Создать пользователя
+    method: post
+    url: "{{ base_url }}/users"
+    headers:
+      Authorization: Bearer {{token}}
+      Content-Type: application/json
+    body: '{"name": "{{name}}"}'
+`
+
+// writeFile записывает содержимое во временный файл и возвращает путь к нему
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadCollection(t *testing.T) {
+	collection, err := LoadCollection(writeFile(t, "api.yaml", collectionYAML))
+	require.NoError(t, err)
+	assert.Equal(t, "Users API", collection.Name)
+	require.Len(t, collection.Requests, 2)
+	assert.Equal(t, "list-users", collection.Requests[0].Name)
+
+	request, err := collection.Request("create-user")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer {{token}}", request.Headers["Authorization"])
+
+	_, err = collection.Request("delete-user")
+	assert.ErrorContains(t, err, "запрос delete-user не найден (доступны: list-users, create-user)")
+
+	collection, err = LoadCollection(writeFile(t, "api.json", `{"requests": [{"name": "health", "url": "http://localhost/health"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "health", collection.Requests[0].Name)
+}
+
+func TestLoadCollection_ClientOptions(t *testing.T) {
+	collection, err := LoadCollection(writeFile(t, "api.yaml", `
+requests:
+  - name: internal
+    url: https://10.0.0.5/health
+    follow: false
+    max_redirects: 3
+    tls:
+      cacert: certs/ca.pem
+      server_name: api.internal
+      min_version: "1.2"
+`))
+	require.NoError(t, err)
+
+	request := collection.Requests[0]
+	require.NotNil(t, request.Follow)
+	assert.False(t, *request.Follow)
+	assert.Equal(t, 3, *request.MaxRedirects)
+	assert.Equal(t, &TLSOptions{CACert: "certs/ca.pem", ServerName: "api.internal", MinVersion: "1.2"}, request.TLS)
+
+	_, err = LoadCollection(writeFile(t, "api.yaml", "requests:\n  - name: a\n    url: http://a\n    tls:\n      sni: a\n"))
+	assert.ErrorContains(t, err, "field sni not found")
+}
+
+func TestLoadCollection_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{"unknown field", "api.yaml", "requests:\n  - name: a\n    url: http://a\n    heders: {}\n", "field heders not found"},
+		{"unknown field json", "api.json", `{"requests": [{"name": "a", "url": "http://a", "body_file": "x"}]}`, "unknown field \"body_file\""},
+		{"no name", "api.yaml", "requests:\n  - url: http://a\n", "у запроса 1 не задано имя"},
+		{"no url", "api.yaml", "requests:\n  - name: a\n", "у запроса a не задан URL"},
+		{"duplicate", "api.yaml", "requests:\n  - name: a\n    url: http://a\n  - name: a\n    url: http://b\n", "запрос a встречается несколько раз"},
+		{"extension", "api.txt", "", "неподдерживаемое расширение файла: .txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCollection(writeFile(t, tt.file, tt.content))
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestSavedRequest_Resolve(t *testing.T) {
+	collection, err := LoadCollection(writeFile(t, "api.yaml", collectionYAML))
+	require.NoError(t, err)
+	request, _ := collection.Request("create-user")
+
+	_, err = request.Resolve(collection.Variables)
+	assert.EqualError(t, err, "не заданы значения переменных: name, token")
+
+	variables := map[string]string{"base_url": "https://api.example.com", "token": "secret", "name": "Ann"}
+	resolved, err := request.Resolve(variables)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", resolved.Method)
+	assert.Equal(t, "https://api.example.com/users", resolved.URL)
+	assert.Equal(t, "Bearer secret", resolved.Headers["Authorization"])
+	assert.Equal(t, `{"name": "Ann"}`, resolved.Body)
+
+	// Исходный запрос не меняется
+	assert.Equal(t, "Bearer {{token}}", request.Headers["Authorization"])
+}
+
+func TestCollectionVariables(t *testing.T) {
+	collection := &Collection{Variables: map[string]string{"base_url": "http://localhost", "token": "dev", "debug": "false"}}
+	staging := writeFile(t, "staging.yaml", "base_url: https://staging.example.com\ntoken: staging\nport: 8443\n")
+	secrets := writeFile(t, "secrets.json", `{"token": "from-secrets"}`)
+
+	variables, err := collectionVariables(collection, []string{staging, secrets}, []string{"debug=true", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"base_url": "https://staging.example.com",
+		"token":    "from-secrets",
+		"port":     "8443",
+		"debug":    "true",
+		"empty":    "",
+	}, variables)
+
+	_, err = collectionVariables(collection, nil, []string{"debug"})
+	assert.ErrorContains(t, err, `неверный формат переменной "debug"`)
+
+	nested := writeFile(t, "nested.yaml", "auth:\n  token: x\n")
+	_, err = collectionVariables(collection, []string{nested}, nil)
+	assert.ErrorContains(t, err, "значение auth должно быть строкой или числом")
+}
+
+func TestCollection_PutSave(t *testing.T) {
+	for _, name := range []string{"api.yaml", "api.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			collection := &Collection{}
+
+			assert.False(t, collection.Put(SavedRequest{Name: "health", URL: "{{base_url}}/health"}))
+			assert.False(t, collection.Put(SavedRequest{Name: "login", Method: "POST", URL: "{{base_url}}/login", Body: `{"user": "{{user}}"}`}))
+			assert.True(t, collection.Put(SavedRequest{Name: "health", URL: "{{base_url}}/healthz"}))
+			require.NoError(t, collection.Save(path))
+
+			loaded, err := LoadCollection(path)
+			require.NoError(t, err)
+			assert.Equal(t, collection, loaded)
+			assert.Equal(t, "{{base_url}}/healthz", loaded.Requests[0].URL)
+		})
+	}
+}
+
+func TestRunSavedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization")+" "+r.UserAgent()+" "+string(body))
+	}))
+	defer server.Close()
+
+	collection, err := LoadCollection(writeFile(t, "api.yaml", collectionYAML))
+	require.NoError(t, err)
+	request, _ := collection.Request("create-user")
+	resolved, err := request.Resolve(map[string]string{"base_url": server.URL, "token": "t", "name": "Ann"})
+	require.NoError(t, err)
+
+	cfg := config.Default()
+	headers := requestHeaders(cfg, resolved.Headers)
+	response, err := NewHTTPClient(5*time.Second).SendRequest(resolved.Method, resolved.URL, headers, []byte(resolved.Body), "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, `POST /users Bearer t DevHelper/1.0 {"name": "Ann"}`, string(response.Body))
+}
+
+func TestRequestHeaders(t *testing.T) {
+	cfg := config.Default()
+	cfg.HTTP.DefaultHeaders = []string{"Accept: application/json", "X-Team: api"}
+
+	headers := requestHeaders(cfg, map[string]string{"x-team": "payments", "Content-Type": "text/plain"})
+	assert.Equal(t, map[string]string{
+		"Accept":       "application/json",
+		"X-Team":       "payments",
+		"Content-Type": "text/plain",
+		"User-Agent":   "DevHelper/1.0",
+	}, headers)
+
+	// User-Agent из заголовков конфигурации имеет приоритет над http.default_user_agent
+	cfg.HTTP.DefaultHeaders = []string{"User-Agent: custom"}
+	assert.Equal(t, "custom", requestHeaders(cfg, nil)["User-Agent"])
+}
+
+func TestPrintCollection(t *testing.T) {
+	collection, err := LoadCollection(writeFile(t, "api.yaml", collectionYAML))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	printCollection(&out, collection)
+	assert.Contains(t, out.String(), "Users API")
+	assert.Regexp(t, `list-users\s+│ GET\s+│ \{\{base_url\}\}/users`, out.String())
+	assert.Regexp(t, `create-user\s+│ POST\s+│ \{\{ base_url \}\}/users\s+│ Создать пользователя`, out.String())
+
+	out.Reset()
+	printCollection(&out, &Collection{})
+	assert.Equal(t, "Коллекция не содержит запросов\n", out.String())
+}
+
+func TestNewCollectionCommand(t *testing.T) {
+	cfg := config.Default()
+
+	reqCmd := NewCollectionCommand(cfg)
+	for _, name := range []string{"run", "list", "save"} {
+		cmd, _, err := reqCmd.Find([]string{name})
+		require.NoError(t, err)
+		assert.Equal(t, name, cmd.Name())
+	}
+
+	// У команды http нет подкоманд, поэтому любой аргумент, в том числе run, считается URL
+	httpCmd := NewCommand(cfg)
+	assert.False(t, httpCmd.HasSubCommands())
+	cmd, args, err := httpCmd.Find([]string{"run"})
+	require.NoError(t, err)
+	assert.Equal(t, httpCmd, cmd)
+	assert.Equal(t, []string{"run"}, args)
+}
@@ -0,0 +1,191 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devhelper/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// requestFlags содержит флаги выполнения запроса, общие для команд http и req run:
+// таймаут, вывод, перенаправления, TLS и время этапов запроса
+type requestFlags struct {
+	timeout      int
+	noColor      bool
+	verbose      bool
+	timing       string
+	follow       bool
+	noFollow     bool
+	maxRedirects int
+	tls          TLSOptions
+}
+
+// bindRequestFlags регистрирует общие флаги запроса, значения по умолчанию берутся из конфигурации
+func bindRequestFlags(cmd *cobra.Command, cfg *config.Config, f *requestFlags) {
+	flags := cmd.Flags()
+	flags.IntVarP(&f.timeout, "timeout", "t", int(cfg.HTTP.Timeout/time.Second), "Таймаут запроса в секундах (для --stream, --output и потоков событий - до получения заголовков)")
+	flags.BoolVar(&f.noColor, "no-color", !cfg.General.ColorEnabled, "Отключить подсветку синтаксиса")
+	flags.BoolVarP(&f.verbose, "verbose", "v", false, "Подробный вывод")
+	flags.StringVar(&f.timing, "timing", "", "Показать время этапов запроса в stderr: table (по умолчанию) или json")
+	flags.Lookup("timing").NoOptDefVal = "table"
+	bindRedirectFlags(cmd, cfg, f)
+	bindTLSFlags(cmd, cfg, &f.tls)
+}
+
+// bindRedirectFlags регистрирует флаги политики перенаправлений
+func bindRedirectFlags(cmd *cobra.Command, cfg *config.Config, f *requestFlags) {
+	flags := cmd.Flags()
+	flags.BoolVarP(&f.follow, "follow", "L", cfg.HTTP.FollowRedirects, "Переходить по перенаправлениям")
+	flags.BoolVar(&f.noFollow, "no-follow", false, "Не переходить по перенаправлениям, показать ответ 3xx")
+	flags.IntVar(&f.maxRedirects, "max-redirects", cfg.HTTP.MaxRedirects, "Максимальное число перенаправлений")
+	cmd.MarkFlagsMutuallyExclusive("follow", "no-follow")
+}
+
+// bindTLSFlags регистрирует флаги параметров TLS
+func bindTLSFlags(cmd *cobra.Command, cfg *config.Config, options *TLSOptions) {
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.Insecure, "insecure", "k", cfg.HTTP.InsecureSSL, "Игнорировать проверку сертификатов SSL")
+	flags.StringVar(&options.CACert, "cacert", "", "Файл с сертификатами CA в формате PEM вместо системных")
+	flags.StringVar(&options.Cert, "cert", "", "Клиентский сертификат в формате PEM для mTLS")
+	flags.StringVar(&options.Key, "key", "", "Закрытый ключ клиентского сертификата (если не входит в файл --cert)")
+	flags.StringVar(&options.MinVersion, "tls-min", "", "Минимальная версия TLS: 1.0, 1.1, 1.2, 1.3")
+	flags.StringVar(&options.MaxVersion, "tls-max", "", "Максимальная версия TLS: 1.0, 1.1, 1.2, 1.3")
+	flags.StringVar(&options.ServerName, "sni", "", "Имя сервера для SNI и проверки сертификата вместо хоста из URL (перенаправления на другие хосты не выполняются)")
+}
+
+// validate проверяет значения флагов, которые не проверяет cobra
+func (f *requestFlags) validate() error {
+	if f.timing != "" && f.timing != "table" && f.timing != "json" {
+		return fmt.Errorf("неизвестный формат --timing: %s (доступны: table, json)", f.timing)
+	}
+	return nil
+}
+
+// clientOptions возвращает параметры HTTP-клиента по флагам
+func (f *requestFlags) clientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout:         time.Duration(f.timeout) * time.Second,
+		FollowRedirects: f.follow && !f.noFollow,
+		MaxRedirects:    f.maxRedirects,
+		TLS:             f.tls,
+	}
+}
+
+// savedClientOptions накладывает параметры перенаправлений и TLS запроса коллекции на
+// параметры клиента. Явно заданные флаги имеют приоритет над коллекцией, относительные
+// пути к файлам сертификатов отсчитываются от каталога коллекции dir.
+// Коллекция хранится в репозитории, поэтому параметры, ослабляющие проверку сервера,
+// применяются с предупреждением в w.
+func savedClientOptions(cmd *cobra.Command, options ClientOptions, request SavedRequest, dir string, w io.Writer) ClientOptions {
+	flags := cmd.Flags()
+	if request.Follow != nil && !flags.Changed("follow") && !flags.Changed("no-follow") {
+		options.FollowRedirects = *request.Follow
+	}
+	if request.MaxRedirects != nil && !flags.Changed("max-redirects") {
+		options.MaxRedirects = *request.MaxRedirects
+	}
+	if request.TLS == nil {
+		return options
+	}
+
+	saved := *request.TLS
+	var weakened []string
+	if saved.Insecure && !flags.Changed("insecure") && !options.TLS.Insecure {
+		options.TLS.Insecure = true
+		weakened = append(weakened, "tls.insecure")
+	}
+	for _, field := range []struct {
+		flag    string
+		key     string
+		target  *string
+		value   string
+		path    bool
+		weakens bool
+	}{
+		{"cacert", "tls.cacert", &options.TLS.CACert, saved.CACert, true, true},
+		{"cert", "tls.cert", &options.TLS.Cert, saved.Cert, true, false},
+		{"key", "tls.key", &options.TLS.Key, saved.Key, true, false},
+		{"tls-min", "tls.min_version", &options.TLS.MinVersion, saved.MinVersion, false, versionBelow(saved.MinVersion, tls.VersionTLS12)},
+		{"tls-max", "tls.max_version", &options.TLS.MaxVersion, saved.MaxVersion, false, versionBelow(saved.MaxVersion, tls.VersionTLS13)},
+		{"sni", "tls.server_name", &options.TLS.ServerName, saved.ServerName, false, true},
+	} {
+		if field.value == "" || flags.Changed(field.flag) {
+			continue
+		}
+		if field.path && !filepath.IsAbs(field.value) {
+			field.value = filepath.Join(dir, field.value)
+		}
+		*field.target = field.value
+		if field.weakens {
+			weakened = append(weakened, field.key)
+		}
+	}
+
+	if len(weakened) > 0 {
+		fmt.Fprintf(w, "Предупреждение: запрос %s коллекции изменяет настройки безопасности TLS: %s\n",
+			request.Name, strings.Join(weakened, ", "))
+	}
+	return options
+}
+
+// versionBelow сообщает, что версия TLS задана и ниже floor.
+// Неизвестные версии не учитываются: ошибку вернет создание клиента.
+func versionBelow(version string, floor uint16) bool {
+	v, err := parseTLSVersion(version)
+	return err == nil && v != 0 && v < floor
+}
+
+// saveTo записывает в запрос коллекции параметры перенаправлений и TLS, заданные флагами явно.
+// Относительные пути к файлам сертификатов пересчитываются от каталога коллекции dir.
+// Возвращает ошибку, если версии TLS неизвестны или минимальная больше максимальной.
+func (f *requestFlags) saveTo(cmd *cobra.Command, request *SavedRequest, dir string) error {
+	if _, _, err := f.tls.versions(); err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("follow") || flags.Changed("no-follow") {
+		follow := f.follow && !f.noFollow
+		request.Follow = &follow
+	}
+	if flags.Changed("max-redirects") {
+		maxRedirects := f.maxRedirects
+		request.MaxRedirects = &maxRedirects
+	}
+
+	saved := f.tls
+	if !flags.Changed("insecure") {
+		saved.Insecure = false
+	}
+	for _, path := range []*string{&saved.CACert, &saved.Cert, &saved.Key} {
+		*path = relativePath(dir, *path)
+	}
+	if saved != (TLSOptions{}) {
+		request.TLS = &saved
+	}
+	return nil
+}
+
+// relativePath возвращает путь относительно каталога dir, если это возможно
+func relativePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(absDir, absPath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,157 @@
+package httpclient
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"devhelper/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestFlagNames флаги, которые должны быть у команд http и req run
+var requestFlagNames = []string{
+	"timeout", "no-color", "verbose", "timing",
+	"follow", "no-follow", "max-redirects",
+	"insecure", "cacert", "cert", "key", "tls-min", "tls-max", "sni",
+}
+
+func TestBindRequestFlags_SharedByCommands(t *testing.T) {
+	cfg := config.Default()
+	httpCmd := NewCommand(cfg)
+	runCmd, _, err := NewCollectionCommand(cfg).Find([]string{"run"})
+	require.NoError(t, err)
+	require.Equal(t, "run", runCmd.Name())
+
+	for _, name := range requestFlagNames {
+		assert.NotNil(t, httpCmd.Flags().Lookup(name), "http --%s", name)
+		assert.NotNil(t, runCmd.Flags().Lookup(name), "run --%s", name)
+	}
+}
+
+// parseRequestFlags регистрирует общие флаги запроса в тестовой команде и разбирает args
+func parseRequestFlags(t *testing.T, cfg *config.Config, args ...string) (*cobra.Command, *requestFlags) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	flags := &requestFlags{}
+	bindRequestFlags(cmd, cfg, flags)
+	require.NoError(t, cmd.ParseFlags(args))
+	require.NoError(t, cmd.ValidateFlagGroups())
+	return cmd, flags
+}
+
+func TestRequestFlags_Validate(t *testing.T) {
+	_, flags := parseRequestFlags(t, config.Default(), "--timing=json")
+	assert.NoError(t, flags.validate())
+
+	_, flags = parseRequestFlags(t, config.Default(), "--timing=xml")
+	assert.EqualError(t, flags.validate(), "неизвестный формат --timing: xml (доступны: table, json)")
+}
+
+func TestSavedClientOptions(t *testing.T) {
+	follow := false
+	maxRedirects := 2
+	request := SavedRequest{
+		Name:         "login",
+		Follow:       &follow,
+		MaxRedirects: &maxRedirects,
+		TLS:          &TLSOptions{CACert: "certs/ca.pem", Cert: "/etc/client.pem", MinVersion: "1.3", ServerName: "api.internal"},
+	}
+
+	// Параметры коллекции заменяют значения из конфигурации
+	var stderr bytes.Buffer
+	cmd, flags := parseRequestFlags(t, config.Default())
+	options := savedClientOptions(cmd, flags.clientOptions(), request, "collections", &stderr)
+	assert.False(t, options.FollowRedirects)
+	assert.Equal(t, 2, options.MaxRedirects)
+	assert.Equal(t, TLSOptions{
+		CACert:     filepath.Join("collections", "certs", "ca.pem"),
+		Cert:       "/etc/client.pem",
+		MinVersion: "1.3",
+		ServerName: "api.internal",
+	}, options.TLS)
+	assert.Equal(t, "Предупреждение: запрос login коллекции изменяет настройки безопасности TLS: tls.cacert, tls.server_name\n", stderr.String())
+
+	// Явно заданные флаги имеют приоритет над коллекцией
+	stderr.Reset()
+	cmd, flags = parseRequestFlags(t, config.Default(), "--follow", "--max-redirects", "5", "--cacert", "ca.pem", "--tls-min", "1.2", "-k")
+	options = savedClientOptions(cmd, flags.clientOptions(), request, "collections", &stderr)
+	assert.True(t, options.FollowRedirects)
+	assert.Equal(t, 5, options.MaxRedirects)
+	assert.Equal(t, "ca.pem", options.TLS.CACert)
+	assert.Equal(t, "1.2", options.TLS.MinVersion)
+	assert.Equal(t, "api.internal", options.TLS.ServerName)
+	assert.True(t, options.TLS.Insecure)
+	assert.Equal(t, "Предупреждение: запрос login коллекции изменяет настройки безопасности TLS: tls.server_name\n", stderr.String())
+
+	// Без параметров в коллекции используются флаги и конфигурация
+	stderr.Reset()
+	cmd, flags = parseRequestFlags(t, config.Default(), "--no-follow")
+	options = savedClientOptions(cmd, flags.clientOptions(), SavedRequest{}, "collections", &stderr)
+	assert.False(t, options.FollowRedirects)
+	assert.Equal(t, 10, options.MaxRedirects)
+	assert.Equal(t, TLSOptions{}, options.TLS)
+	assert.Empty(t, stderr.String())
+}
+
+func TestSavedClientOptions_WeakTLSWarning(t *testing.T) {
+	request := SavedRequest{
+		Name: "health",
+		TLS:  &TLSOptions{Insecure: true, Cert: "client.pem", MinVersion: "1.0", MaxVersion: "1.3"},
+	}
+
+	var stderr bytes.Buffer
+	cmd, flags := parseRequestFlags(t, config.Default())
+	options := savedClientOptions(cmd, flags.clientOptions(), request, "", &stderr)
+	assert.True(t, options.TLS.Insecure)
+	assert.Equal(t, "Предупреждение: запрос health коллекции изменяет настройки безопасности TLS: tls.insecure, tls.min_version\n", stderr.String())
+
+	// Клиентский сертификат и современные версии TLS не ослабляют проверку сервера
+	stderr.Reset()
+	request.TLS = &TLSOptions{Cert: "client.pem", MinVersion: "1.2", MaxVersion: "1.3"}
+	savedClientOptions(cmd, flags.clientOptions(), request, "", &stderr)
+	assert.Empty(t, stderr.String())
+
+	// Явный -k означает, что пользователь сам отключил проверку сертификатов
+	stderr.Reset()
+	request.TLS = &TLSOptions{Insecure: true}
+	cmd, flags = parseRequestFlags(t, config.Default(), "-k")
+	savedClientOptions(cmd, flags.clientOptions(), request, "", &stderr)
+	assert.Empty(t, stderr.String())
+}
+
+func TestRequestFlags_SaveTo(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	// Незаданные флаги не сохраняются, insecure из конфигурации тоже
+	cfg := config.Default()
+	cfg.HTTP.InsecureSSL = true
+	cmd, flags := parseRequestFlags(t, cfg)
+	var request SavedRequest
+	require.NoError(t, flags.saveTo(cmd, &request, dir))
+	assert.Nil(t, request.Follow)
+	assert.Nil(t, request.MaxRedirects)
+	assert.Nil(t, request.TLS)
+
+	// Пути к сертификатам сохраняются относительно каталога коллекции
+	cmd, flags = parseRequestFlags(t, config.Default(), "--no-follow", "--max-redirects", "3", "--cacert", "certs/ca.pem", "--sni", "api.internal")
+	require.NoError(t, flags.saveTo(cmd, &request, "collections"))
+	require.NotNil(t, request.Follow)
+	assert.False(t, *request.Follow)
+	assert.Equal(t, 3, *request.MaxRedirects)
+	assert.Equal(t, &TLSOptions{CACert: "../certs/ca.pem", ServerName: "api.internal"}, request.TLS)
+}
+
+func TestRequestFlags_SaveToValidatesVersions(t *testing.T) {
+	cmd, flags := parseRequestFlags(t, config.Default(), "--tls-min", "9.9")
+	var request SavedRequest
+	assert.EqualError(t, flags.saveTo(cmd, &request, ""), "неизвестная версия TLS: 9.9 (доступны: 1.0, 1.1, 1.2, 1.3)")
+	assert.Nil(t, request.TLS)
+
+	cmd, flags = parseRequestFlags(t, config.Default(), "--tls-min", "1.3", "--tls-max", "1.2")
+	assert.EqualError(t, flags.saveTo(cmd, &request, ""), "минимальная версия TLS 1.3 больше максимальной 1.2")
+	assert.Nil(t, request.TLS)
+}
@@ -2,14 +2,21 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"devhelper/pkg/config"
+	"devhelper/pkg/utils"
 	"github.com/alecthomas/chroma"
 	"github.com/alecthomas/chroma/formatters"
 	"github.com/alecthomas/chroma/lexers"
@@ -23,40 +30,113 @@ import (
 // HTTPClient представляет HTTP-клиент
 type HTTPClient struct {
 	client *http.Client
+
+	// insecureClient клиент без проверки сертификатов для запросов с insecure,
+	// создается при первом таком запросе
+	insecureClient *http.Client
+	insecureOnce   sync.Once
+}
+
+// ClientOptions содержит параметры HTTP-клиента
+type ClientOptions struct {
+	// Timeout таймаут запроса, включая перенаправления. В SendRequest ограничивает и получение
+	// тела, в OpenRequest - только подключение и получение заголовков ответа
+	Timeout time.Duration
+	// FollowRedirects переходить по перенаправлениям 3xx
+	FollowRedirects bool
+	// MaxRedirects максимальное число перенаправлений, при превышении запрос завершается ошибкой
+	MaxRedirects int
+
+	// TLS параметры TLS-соединений
+	TLS TLSOptions
+}
+
+// DefaultClientOptions возвращает параметры клиента по умолчанию
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout:         30 * time.Second,
+		FollowRedirects: true,
+		MaxRedirects:    10,
+	}
 }
 
 // NewHTTPClient создает новый HTTP-клиент
 func NewHTTPClient(timeout time.Duration) *HTTPClient {
+	options := DefaultClientOptions()
+	options.Timeout = timeout
+	// Без файлов сертификатов и версий TLS создание клиента не завершается ошибкой
+	client, _ := NewHTTPClientWithOptions(options)
+	return client
+}
+
+// NewHTTPClientWithOptions создает новый HTTP-клиент с заданными параметрами.
+// Возвращает ошибку, если не удалось загрузить сертификаты или параметры TLS неверны.
+func NewHTTPClientWithOptions(options ClientOptions) (*HTTPClient, error) {
+	tlsConfig, err := options.TLS.config()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
 	return &HTTPClient{
 		client: &http.Client{
-			Timeout: timeout,
+			Transport:     transport,
+			Timeout:       options.Timeout,
+			CheckRedirect: redirectPolicy(options.FollowRedirects, options.MaxRedirects, options.TLS.ServerName),
 		},
+	}, nil
+}
+
+// httpClient возвращает клиент для запроса, при insecure - без проверки сертификатов
+func (c *HTTPClient) httpClient(insecure bool) *http.Client {
+	transport, ok := c.client.Transport.(*http.Transport)
+	if !insecure || !ok || (transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify) {
+		return c.client
 	}
+
+	c.insecureOnce.Do(func() {
+		insecureTransport := transport.Clone()
+		if insecureTransport.TLSClientConfig == nil {
+			insecureTransport.TLSClientConfig = &tls.Config{}
+		}
+		insecureTransport.TLSClientConfig.InsecureSkipVerify = true
+		client := *c.client
+		client.Transport = insecureTransport
+		c.insecureClient = &client
+	})
+	return c.insecureClient
 }
 
 // NewCommand создает новую команду HTTP-клиента
-func NewCommand() *cobra.Command {
+func NewCommand(cfg *config.Config) *cobra.Command {
 	var (
 		method      string
 		headers     []string
 		data        string
 		dataFile    string
-		timeout     int
-		noColor     bool
 		outputFile  string
-		verbose     bool
-		insecure    bool
 		contentType string
 		username    string
 		password    string
 		json        bool
+		userAgent   string
+		stream      bool
+		flags       requestFlags
 	)
 
 	httpCmd := &cobra.Command{
 		Use:   "http [url]",
 		Short: "HTTP-клиент для тестирования API",
-		Long:  "Простой HTTP-клиент для отправки запросов и тестирования API.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Простой HTTP-клиент для отправки запросов и тестирования API.
+С флагом --output ответ записывается в файл по мере получения, при известном размере
+выводится индикатор с объемом, скоростью и оставшимся временем. С флагом --stream
+тело ответа выводится без буферизации, ответы text/event-stream выводятся так всегда.
+С флагом --timing в stderr выводится время этапов запроса: DNS, TCP-подключение,
+TLS-рукопожатие, ожидание первого байта и получение тела (--timing=json для скриптов).
+Запросы можно хранить в коллекциях YAML или JSON и выполнять командой devhelper req.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			url := args[0]
 
@@ -65,21 +145,29 @@ func NewCommand() *cobra.Command {
 				method = "GET"
 			}
 
-			// Устанавливаем HTTP-клиент с таймаутом
-			client := NewHTTPClient(time.Duration(timeout) * time.Second)
+			if err := flags.validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+
+			// Устанавливаем HTTP-клиент с таймаутом и политикой перенаправлений
+			client, err := NewHTTPClientWithOptions(flags.clientOptions())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка настройки HTTP-клиента: %s\n", err)
+				os.Exit(1)
+			}
 
 			// Если указан флаг --json, устанавливаем соответствующий Content-Type
 			if json {
 				contentType = "application/json"
 			}
 
-			// Собираем заголовки
-			headerMap := make(map[string]string)
-			for _, header := range headers {
-				parts := strings.SplitN(header, ":", 2)
-				if len(parts) == 2 {
-					headerMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-				}
+			// Собираем заголовки: заголовки из конфигурации, затем переопределяющие их флаги -H
+			headerMap := parseHeaders(append(append([]string{}, cfg.HTTP.DefaultHeaders...), headers...))
+
+			// User-Agent из флага -H имеет приоритет над --user-agent
+			if _, ok := headerMap["User-Agent"]; !ok && userAgent != "" {
+				headerMap["User-Agent"] = userAgent
 			}
 
 			// Если указан content-type, добавляем его в заголовки
@@ -105,48 +193,67 @@ func NewCommand() *cobra.Command {
 			s.Suffix = " Выполнение запроса..."
 			s.Start()
 
-			// Выполняем запрос
-			response, err := client.SendRequest(method, url, headerMap, requestBody, username, password, insecure)
-			s.Stop()
-
+			// Выполняем запрос, тело ответа читается по мере получения
+			response, responseBody, err := client.OpenRequest(method, url, headerMap, requestBody, username, password, flags.tls.Insecure)
 			if err != nil {
+				s.Stop()
 				fmt.Fprintf(os.Stderr, "Ошибка при выполнении запроса: %s\n", err)
 				os.Exit(1)
 			}
+			defer responseBody.Close()
 
-			// Если указан выходной файл, сохраняем ответ в файл
+			// Если указан выходной файл, сохраняем ответ в файл без загрузки в память.
+			// При известном размере вместо спиннера выводится индикатор выполнения.
 			if outputFile != "" {
-				if err := os.WriteFile(outputFile, response.Body, 0644); err != nil {
+				var progressOut io.Writer
+				if response.ContentLength > 0 && utils.IsTerminal(int(os.Stderr.Fd())) {
+					s.Stop()
+					progressOut = os.Stderr
+				}
+				written, err := saveBody(outputFile, responseBody, response.ContentLength, progressOut)
+				s.Stop()
+				if err != nil {
 					fmt.Fprintf(os.Stderr, "Ошибка при сохранении ответа в файл: %s\n", err)
 					os.Exit(1)
 				}
-				fmt.Printf("Ответ сохранен в файл: %s\n", outputFile)
+				fmt.Printf("Ответ сохранен в файл: %s (%s)\n", outputFile, utils.FormatBytes(uint64(written)))
+				reportTiming(flags.timing, response)
 				return
 			}
 
-			// Выводим информацию о запросе в вербозном режиме
-			if verbose {
-				// Вывод информации о запросе
-				fmt.Printf("> %s %s\n", method, url)
-				for key, value := range headerMap {
-					fmt.Printf("> %s: %s\n", key, value)
-				}
-				if len(requestBody) > 0 {
-					fmt.Println(">")
-					fmt.Println(string(requestBody))
+			// Потоки событий не завершаются, поэтому выводятся по мере получения всегда
+			streaming := stream || isEventStream(response.Headers["Content-Type"])
+			if !streaming {
+				response.Body, err = readBody(responseBody, time.Duration(flags.timeout)*time.Second, response.TotalTime)
+				if err != nil {
+					s.Stop()
+					fmt.Fprintf(os.Stderr, "Ошибка при выполнении запроса: ошибка чтения ответа: %s\n", err)
+					os.Exit(1)
 				}
-				fmt.Println()
+			}
+			s.Stop()
+
+			// Выводим информацию о запросе в вербозном режиме
+			if flags.verbose {
+				printRequest(method, url, headerMap, requestBody, response)
 			}
 
-			// Выводим информацию о статусе
-			statusColor := color.New(color.FgCyan).SprintFunc()
-			fmt.Printf("%s %s\n", statusColor(response.Status), response.Proto)
+			// Выводим статус и заголовки ответа
+			printStatus(response)
 
-			// Выводим заголовки ответа
-			printHeaders(response.Headers)
+			// В потоковом режиме тело выводится без форматирования по мере получения
+			if streaming {
+				if _, err := io.Copy(os.Stdout, responseBody); err != nil {
+					fmt.Fprintf(os.Stderr, "\nОшибка чтения ответа: %s\n", err)
+					os.Exit(1)
+				}
+				reportTiming(flags.timing, response)
+				return
+			}
 
 			// Выводим тело ответа с подсветкой синтаксиса, если это возможно
-			printResponseBody(response.Body, response.Headers["Content-Type"], !noColor)
+			printResponseBody(response.Body, response.Headers["Content-Type"], !flags.noColor)
+			reportTiming(flags.timing, response)
 		},
 	}
 
@@ -154,36 +261,133 @@ func NewCommand() *cobra.Command {
 	httpCmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "HTTP-заголовки (формат: 'Ключ: Значение')")
 	httpCmd.Flags().StringVarP(&data, "data", "d", "", "Данные для отправки в теле запроса")
 	httpCmd.Flags().StringVarP(&dataFile, "data-file", "f", "", "Файл с данными для отправки в теле запроса")
-	httpCmd.Flags().IntVarP(&timeout, "timeout", "t", 30, "Таймаут запроса в секундах")
-	httpCmd.Flags().BoolVar(&noColor, "no-color", false, "Отключить подсветку синтаксиса")
 	httpCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Сохранить ответ в файл")
-	httpCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Подробный вывод")
-	httpCmd.Flags().BoolVarP(&insecure, "insecure", "k", false, "Игнорировать проверку сертификатов SSL")
 	httpCmd.Flags().StringVar(&contentType, "content-type", "", "Тип содержимого (Content-Type)")
 	httpCmd.Flags().StringVarP(&username, "user", "u", "", "Имя пользователя и пароль для базовой аутентификации (формат: 'username:password')")
 	httpCmd.Flags().StringVarP(&password, "password", "p", "", "Пароль для базовой аутентификации (если не указан в --user)")
 	httpCmd.Flags().BoolVarP(&json, "json", "j", false, "Использовать Content-Type: application/json")
+	httpCmd.Flags().BoolVarP(&stream, "stream", "N", false, "Выводить тело ответа по мере получения, без буферизации и подсветки")
+	httpCmd.Flags().StringVarP(&userAgent, "user-agent", "A", cfg.HTTP.DefaultUserAgent, "Заголовок User-Agent")
+	bindRequestFlags(httpCmd, cfg, &flags)
 
 	return httpCmd
 }
 
+// reportTiming выводит время этапов запроса в stderr в заданном формате, чтобы
+// отчет не смешивался с телом ответа
+func reportTiming(format string, response HTTPResponse) {
+	switch format {
+	case "table":
+		fmt.Fprintln(os.Stderr)
+		printTiming(os.Stderr, response.trace)
+	case "json":
+		if err := printTimingJSON(os.Stderr, response.Timing()); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка вывода времени запроса: %s\n", err)
+		}
+	}
+}
+
 // HTTPResponse представляет ответ на HTTP-запрос
 type HTTPResponse struct {
-	Status    string
-	Proto     string
-	Headers   map[string]string
-	Body      []byte
-	TotalTime time.Duration
+	Status  string
+	Proto   string
+	Headers map[string]string
+	Body    []byte
+	// ContentLength размер тела из заголовка Content-Length, -1 если размер неизвестен
+	ContentLength int64
+	TotalTime     time.Duration
+	// Redirects цепочка перенаправлений, пройденных до получения ответа
+	Redirects []Redirect
+	// TLS параметры TLS-соединения, nil для запросов по HTTP
+	TLS *tls.ConnectionState
+
+	trace *timingTrace
+}
+
+// Timing возвращает длительности этапов запроса. Время получения тела
+// учитывается после того, как тело прочитано до конца или закрыто.
+func (r HTTPResponse) Timing() Timing {
+	if r.trace == nil {
+		return Timing{}
+	}
+	return r.trace.timing()
 }
 
 // SendRequest отправляет HTTP-запрос и возвращает ответ
 func (c *HTTPClient) SendRequest(method, url string, headers map[string]string, body []byte, username, password string, insecure bool) (HTTPResponse, error) {
 	startTime := time.Now()
 
+	response, responseBody, err := c.send(context.Background(), c.httpClient(insecure), method, url, headers, body, username, password)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+	defer responseBody.Close()
+
+	// Читаем тело ответа
+	response.Body, err = io.ReadAll(responseBody)
+	if err != nil {
+		return HTTPResponse{}, fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+	response.TotalTime = time.Since(startTime)
+
+	return response, nil
+}
+
+// errHeaderTimeout причина отмены запроса, не получившего заголовки ответа за время таймаута
+var errHeaderTimeout = errors.New("превышен таймаут ожидания ответа")
+
+// OpenRequest отправляет HTTP-запрос и возвращает ответ после получения заголовков.
+// Тело ответа не буферизуется: оно читается из возвращаемого потока по мере получения,
+// поток должен быть закрыт вызывающим. Флаг insecure отключает проверку сертификатов
+// для этого запроса. Таймаут клиента ограничивает только подключение и получение
+// заголовков, поэтому потоки событий и загрузка больших файлов не прерываются по таймауту.
+func (c *HTTPClient) OpenRequest(method, url string, headers map[string]string, body []byte, username, password string, insecure bool) (HTTPResponse, io.ReadCloser, error) {
+	client := *c.httpClient(insecure)
+	timeout := client.Timeout
+	client.Timeout = 0
+
+	// Таймер отменяет запрос, если заголовки ответа не получены вовремя
+	ctx, cancel := context.WithCancelCause(context.Background())
+	timer := time.AfterFunc(timeout, func() { cancel(errHeaderTimeout) })
+	if timeout <= 0 {
+		timer.Stop()
+	}
+
+	response, responseBody, err := c.send(ctx, &client, method, url, headers, body, username, password)
+	if timeout > 0 && !timer.Stop() {
+		if err == nil {
+			responseBody.Close()
+		}
+		err = fmt.Errorf("ошибка выполнения запроса: %w (%s)", errHeaderTimeout, timeout)
+	}
+	if err != nil {
+		cancel(nil)
+		return HTTPResponse{}, nil, err
+	}
+	return response, cancelBody{ReadCloser: responseBody, cancel: cancel}, nil
+}
+
+// cancelBody освобождает контекст запроса при закрытии тела ответа
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelCauseFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel(nil)
+	return err
+}
+
+// send отправляет HTTP-запрос клиентом client в контексте ctx и возвращает ответ
+// после получения заголовков вместе с потоком тела
+func (c *HTTPClient) send(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body []byte, username, password string) (HTTPResponse, io.ReadCloser, error) {
+	startTime := time.Now()
+
 	// Создаем запрос
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
 	if err != nil {
-		return HTTPResponse{}, fmt.Errorf("ошибка создания запроса: %w", err)
+		return HTTPResponse{}, nil, fmt.Errorf("ошибка создания запроса: %w", err)
 	}
 
 	// Добавляем заголовки
@@ -201,17 +405,16 @@ func (c *HTTPClient) SendRequest(method, url string, headers map[string]string,
 		req.SetBasicAuth(username, password)
 	}
 
-	// Выполняем запрос
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return HTTPResponse{}, fmt.Errorf("ошибка выполнения запроса: %w", err)
-	}
-	defer resp.Body.Close()
+	// Замеряем этапы выполнения запроса и записываем цепочку перенаправлений
+	trace := newTimingTrace()
+	redirects := &[]Redirect{}
+	ctx = context.WithValue(ctx, redirectsKey{}, redirects)
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace.clientTrace()))
 
-	// Читаем тело ответа
-	responseBody, err := io.ReadAll(resp.Body)
+	// Выполняем запрос
+	resp, err := client.Do(req)
 	if err != nil {
-		return HTTPResponse{}, fmt.Errorf("ошибка чтения ответа: %w", err)
+		return HTTPResponse{}, nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
 	}
 
 	// Собираем заголовки ответа
@@ -221,12 +424,65 @@ func (c *HTTPClient) SendRequest(method, url string, headers map[string]string,
 	}
 
 	return HTTPResponse{
-		Status:    fmt.Sprintf("%d %s", resp.StatusCode, resp.Status),
-		Proto:     resp.Proto,
-		Headers:   responseHeaders,
-		Body:      responseBody,
-		TotalTime: time.Since(startTime),
-	}, nil
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, resp.Status),
+		Proto:         resp.Proto,
+		Headers:       responseHeaders,
+		ContentLength: resp.ContentLength,
+		TotalTime:     time.Since(startTime),
+		Redirects:     *redirects,
+		TLS:           resp.TLS,
+		trace:         trace,
+	}, timedBody{ReadCloser: resp.Body, trace: trace}, nil
+}
+
+// readBody читает тело ответа целиком. Если запрос, на который до получения заголовков
+// ушло elapsed, не укладывается в timeout, поток закрывается и возвращается ошибка.
+// Нулевой timeout не ограничивает чтение.
+func readBody(body io.ReadCloser, timeout, elapsed time.Duration) ([]byte, error) {
+	if timeout <= 0 {
+		return io.ReadAll(body)
+	}
+	timer := time.AfterFunc(timeout-elapsed, func() { body.Close() })
+	data, err := io.ReadAll(body)
+	if !timer.Stop() {
+		return nil, fmt.Errorf("превышен таймаут запроса (%s)", timeout)
+	}
+	return data, err
+}
+
+// parseHeaders разбирает заголовки вида "Ключ: Значение", последующие заголовки
+// переопределяют предыдущие с тем же ключом. Строки без двоеточия пропускаются.
+func parseHeaders(lines []string) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range lines {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) == 2 {
+			headers[http.CanonicalHeaderKey(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+		}
+	}
+	return headers
+}
+
+// printRequest выводит запрос, цепочку перенаправлений и параметры TLS в вербозном режиме
+func printRequest(method, url string, headers map[string]string, body []byte, response HTTPResponse) {
+	fmt.Printf("> %s %s\n", method, url)
+	for key, value := range headers {
+		fmt.Printf("> %s: %s\n", key, value)
+	}
+	if len(body) > 0 {
+		fmt.Println(">")
+		fmt.Println(string(body))
+	}
+	fmt.Println()
+	printRedirects(os.Stdout, response.Redirects)
+	printTLS(os.Stdout, response.TLS)
+}
+
+// printStatus выводит статус и заголовки HTTP-ответа
+func printStatus(response HTTPResponse) {
+	statusColor := color.New(color.FgCyan).SprintFunc()
+	fmt.Printf("%s %s\n", statusColor(response.Status), response.Proto)
+	printHeaders(response.Headers)
 }
 
 // printHeaders выводит заголовки HTTP-ответа
@@ -2,12 +2,15 @@ package httpclient
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTPClient_SendRequest(t *testing.T) {
@@ -165,3 +168,89 @@ func TestNewHTTPClient(t *testing.T) {
 	assert.NotNil(t, client.client)
 	assert.Equal(t, timeout, client.client.Timeout)
 }
+
+func TestHTTPClient_OpenRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+		fmt.Fprint(w, "data: second\n\n")
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	response, body, err := client.OpenRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.NoError(t, err)
+	defer body.Close()
+
+	assert.Equal(t, "text/event-stream", response.Headers["Content-Type"])
+	assert.Equal(t, int64(-1), response.ContentLength)
+
+	// Первое событие доступно до завершения ответа
+	buf := make([]byte, len("data: first\n\n"))
+	_, err = io.ReadFull(body, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: first\n\n", string(buf))
+
+	close(release)
+	rest, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: second\n\n", string(rest))
+}
+
+// slowBodyHandler отправляет заголовки сразу, а тело - частями в течение duration
+func slowBodyHandler(duration time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for deadline := time.Now().Add(duration); time.Now().Before(deadline); {
+			fmt.Fprint(w, "chunk\n")
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+}
+
+func TestHTTPClient_OpenRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(slowBodyHandler(300 * time.Millisecond))
+	defer server.Close()
+	client := NewHTTPClient(100 * time.Millisecond)
+
+	// Таймаут не прерывает получение тела потока
+	_, body, err := client.OpenRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Greater(t, strings.Count(string(data), "chunk\n"), 5)
+
+	// SendRequest ограничивает запрос вместе с получением тела
+	_, err = client.SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.Error(t, err)
+
+	// Чтение тела целиком ограничено оставшимся временем таймаута
+	_, body, err = client.OpenRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	_, err = readBody(body, 100*time.Millisecond, 0)
+	assert.ErrorContains(t, err, "превышен таймаут запроса")
+	body.Close()
+}
+
+func TestHTTPClient_OpenRequestHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(50 * time.Millisecond)
+	_, _, err := client.OpenRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.ErrorContains(t, err, "превышен таймаут ожидания ответа (50ms)")
+}
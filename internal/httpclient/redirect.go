@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Redirect описывает один переход в цепочке перенаправлений
+type Redirect struct {
+	// Method и URL запроса, на который получено перенаправление
+	Method string
+	URL    string
+	// Status статус ответа, например "301 Moved Permanently"
+	Status string
+	// Location значение заголовка Location
+	Location string
+}
+
+// redirectsKey ключ контекста запроса для записи цепочки перенаправлений
+type redirectsKey struct{}
+
+// redirectPolicy возвращает функцию http.Client.CheckRedirect, которая записывает
+// цепочку перенаправлений и ограничивает число переходов.
+//
+// Метод, тело и заголовки при переходе выбирает net/http: на 301, 302 и 303 запрос
+// повторяется методом GET без тела и заголовка Content-Type (HEAD остается HEAD),
+// на 307 и 308 метод и тело сохраняются. Authorization и Cookie не передаются на другой хост.
+//
+// Имя serverName (--sni) задается в общей конфигурации TLS и проверяется у каждого соединения,
+// поэтому при нем разрешены только перенаправления в пределах исходного хоста: сертификат
+// другого хоста проверялся бы по чужому имени.
+func redirectPolicy(follow bool, maxRedirects int, serverName string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !follow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("превышено максимальное число перенаправлений: %d", maxRedirects)
+		}
+		if origin := via[0].URL.Host; serverName != "" && req.URL.Host != origin {
+			return fmt.Errorf("перенаправление на хост %s не выполняется: имя сервера %s задано только для %s", req.URL.Host, serverName, origin)
+		}
+
+		prev := via[len(via)-1]
+		if redirects, ok := req.Context().Value(redirectsKey{}).(*[]Redirect); ok && req.Response != nil {
+			*redirects = append(*redirects, Redirect{
+				Method:   prev.Method,
+				URL:      prev.URL.String(),
+				Status:   req.Response.Status,
+				Location: req.Response.Header.Get("Location"),
+			})
+		}
+		return nil
+	}
+}
+
+// printRedirects выводит цепочку перенаправлений со статусами и заголовками Location
+func printRedirects(w io.Writer, redirects []Redirect) {
+	for _, redirect := range redirects {
+		fmt.Fprintf(w, "* %s %s\n", redirect.Method, redirect.URL)
+		fmt.Fprintf(w, "* %s -> %s\n", redirect.Status, redirect.Location)
+	}
+	if len(redirects) > 0 {
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redirectServer перенаправляет /redirect/{код} на /echo с заданным статусом,
+// /loop/{n} - на /loop/{n+1}, а /echo возвращает метод, Content-Type и тело запроса
+func redirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect/{code}", func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(r.PathValue("code"))
+		http.Redirect(w, r, "/echo", code)
+	})
+	mux.HandleFunc("/loop/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("n"))
+		http.Redirect(w, r, "/loop/"+strconv.Itoa(n+1), http.StatusFound)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+r.Header.Get("Content-Type")+" "+string(body))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedirect_MethodAndBody(t *testing.T) {
+	server := redirectServer(t)
+	client := NewHTTPClient(5 * time.Second)
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	tests := []struct {
+		code     int
+		expected string
+	}{
+		{http.StatusMovedPermanently, "GET  "},
+		{http.StatusFound, "GET  "},
+		{http.StatusSeeOther, "GET  "},
+		{http.StatusTemporaryRedirect, `POST application/json {"id":1}`},
+		{http.StatusPermanentRedirect, `POST application/json {"id":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.code), func(t *testing.T) {
+			url := server.URL + "/redirect/" + strconv.Itoa(tt.code)
+			response, err := client.SendRequest("POST", url, headers, []byte(`{"id":1}`), "", "", false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(response.Body))
+
+			require.Len(t, response.Redirects, 1)
+			assert.Equal(t, Redirect{
+				Method:   "POST",
+				URL:      url,
+				Status:   strconv.Itoa(tt.code) + " " + http.StatusText(tt.code),
+				Location: "/echo",
+			}, response.Redirects[0])
+		})
+	}
+}
+
+func TestRedirect_NoFollow(t *testing.T) {
+	server := redirectServer(t)
+	client, err := NewHTTPClientWithOptions(ClientOptions{Timeout: 5 * time.Second, FollowRedirects: false, MaxRedirects: 10})
+	require.NoError(t, err)
+
+	response, err := client.SendRequest("GET", server.URL+"/redirect/302", nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(response.Status, "302"))
+	assert.Equal(t, "/echo", response.Headers["Location"])
+	assert.Empty(t, response.Redirects)
+}
+
+func TestRedirect_MaxRedirects(t *testing.T) {
+	server := redirectServer(t)
+
+	client, err := NewHTTPClientWithOptions(ClientOptions{Timeout: 5 * time.Second, FollowRedirects: true, MaxRedirects: 3})
+	require.NoError(t, err)
+	_, err = client.SendRequest("GET", server.URL+"/loop/0", nil, nil, "", "", false)
+	assert.ErrorContains(t, err, "превышено максимальное число перенаправлений: 3")
+
+	response, err := client.SendRequest("GET", server.URL+"/redirect/301", nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Len(t, response.Redirects, 1)
+
+	// При нулевом ограничении перенаправление считается ошибкой
+	client, err = NewHTTPClientWithOptions(ClientOptions{Timeout: 5 * time.Second, FollowRedirects: true, MaxRedirects: 0})
+	require.NoError(t, err)
+	_, err = client.SendRequest("GET", server.URL+"/redirect/301", nil, nil, "", "", false)
+	assert.ErrorContains(t, err, "превышено максимальное число перенаправлений: 0")
+}
+
+func TestRedirectPolicy_ServerName(t *testing.T) {
+	origin, err := http.NewRequest("GET", "https://10.0.0.5/old", nil)
+	require.NoError(t, err)
+	sameHost, err := http.NewRequest("GET", "https://10.0.0.5/new", nil)
+	require.NoError(t, err)
+	otherHost, err := http.NewRequest("GET", "https://cdn.example.com/new", nil)
+	require.NoError(t, err)
+
+	// С --sni перенаправления в пределах исходного хоста выполняются, на другой хост - нет
+	policy := redirectPolicy(true, 10, "api.internal")
+	assert.NoError(t, policy(sameHost, []*http.Request{origin}))
+	assert.EqualError(t, policy(otherHost, []*http.Request{origin, sameHost}),
+		"перенаправление на хост cdn.example.com не выполняется: имя сервера api.internal задано только для 10.0.0.5")
+
+	// Без --sni ограничения по хосту нет
+	assert.NoError(t, redirectPolicy(true, 10, "")(otherHost, []*http.Request{origin}))
+}
+
+func TestPrintRedirects(t *testing.T) {
+	var out bytes.Buffer
+	printRedirects(&out, nil)
+	assert.Empty(t, out.String())
+
+	printRedirects(&out, []Redirect{
+		{Method: "POST", URL: "http://example.com/old", Status: "308 Permanent Redirect", Location: "/new"},
+		{Method: "POST", URL: "http://example.com/new", Status: "303 See Other", Location: "https://example.com/done"},
+	})
+	assert.Equal(t, `* POST http://example.com/old
+* 308 Permanent Redirect -> /new
+* POST http://example.com/new
+* 303 See Other -> https://example.com/done
+
+`, out.String())
+}
@@ -0,0 +1,83 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+)
+
+// isEventStream проверяет, является ли ответ потоком событий (Server-Sent Events)
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// saveBody записывает тело ответа в файл по мере получения и возвращает число записанных байт.
+// Если progressOut не nil, в него выводится индикатор выполнения.
+func saveBody(path string, body io.Reader, size int64, progressOut io.Writer) (int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+
+	written, err := copyWithProgress(file, body, size, filepath.Base(path), progressOut)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("ошибка записи файла: %w", closeErr)
+	}
+	return written, err
+}
+
+// copyWithProgress копирует тело ответа размером size в w. Если out не nil и размер известен,
+// в out выводится индикатор выполнения с объемом, скоростью и оставшимся временем.
+func copyWithProgress(w io.Writer, body io.Reader, size int64, message string, out io.Writer) (int64, error) {
+	if out == nil || size <= 0 {
+		written, err := io.Copy(w, body)
+		if err != nil {
+			return written, fmt.Errorf("ошибка чтения ответа: %w", err)
+		}
+		return written, nil
+	}
+
+	tracker := &progress.Tracker{Message: message, Total: size, Units: progress.UnitsBytes}
+
+	pw := progress.NewWriter()
+	pw.SetOutputWriter(out)
+	pw.SetAutoStop(true)
+	pw.SetTrackerPosition(progress.PositionRight)
+	pw.SetUpdateFrequency(100 * time.Millisecond)
+	pw.Style().Visibility.ETA = true
+	pw.Style().Visibility.Speed = true
+	pw.AppendTracker(tracker)
+
+	done := make(chan struct{})
+	go func() {
+		pw.Render()
+		close(done)
+	}()
+
+	written, err := io.Copy(io.MultiWriter(w, trackerWriter{tracker}), body)
+	if err != nil {
+		tracker.MarkAsErrored()
+		err = fmt.Errorf("ошибка чтения ответа: %w", err)
+	} else {
+		tracker.MarkAsDone()
+	}
+	<-done
+
+	return written, err
+}
+
+// trackerWriter увеличивает значение индикатора выполнения на число записанных байт
+type trackerWriter struct {
+	tracker *progress.Tracker
+}
+
+func (t trackerWriter) Write(p []byte) (int, error) {
+	t.tracker.Increment(int64(len(p)))
+	return len(p), nil
+}
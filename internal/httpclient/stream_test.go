@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEventStream(t *testing.T) {
+	assert.True(t, isEventStream("text/event-stream"))
+	assert.True(t, isEventStream("text/event-stream; charset=utf-8"))
+	assert.False(t, isEventStream("application/json"))
+	assert.False(t, isEventStream(""))
+}
+
+func TestCopyWithProgress(t *testing.T) {
+	data := strings.Repeat("x", 64*1024)
+
+	// Без индикатора тело просто копируется
+	var w bytes.Buffer
+	written, err := copyWithProgress(&w, strings.NewReader(data), int64(len(data)), "body", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), written)
+	assert.Equal(t, data, w.String())
+
+	// Индикатор выводится в отдельный поток и не попадает в тело
+	var out bytes.Buffer
+	w.Reset()
+	written, err = copyWithProgress(&w, strings.NewReader(data), int64(len(data)), "body", &out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), written)
+	assert.Equal(t, data, w.String())
+	assert.Contains(t, out.String(), "body")
+	assert.Contains(t, out.String(), "65.54KB")
+}
+
+// failingReader возвращает данные, а затем ошибку, как оборванное соединение
+type failingReader struct {
+	data io.Reader
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestSaveBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+
+	written, err := saveBody(path, strings.NewReader("content"), -1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	var out bytes.Buffer
+	_, err = saveBody(path, failingReader{strings.NewReader("partial")}, 100, &out)
+	assert.ErrorContains(t, err, "ошибка чтения ответа: connection reset")
+
+	_, err = saveBody(filepath.Join(t.TempDir(), "missing", "file"), strings.NewReader(""), -1, nil)
+	assert.ErrorContains(t, err, "ошибка создания файла")
+}
@@ -0,0 +1,222 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Timing содержит длительности этапов HTTP-запроса. Этапы, которых не было
+// (например, DNS и подключение при повторном использовании соединения), равны нулю.
+type Timing struct {
+	// DNSLookup время разрешения имени
+	DNSLookup time.Duration
+	// TCPConnect время установки TCP-соединения
+	TCPConnect time.Duration
+	// TLSHandshake время TLS-рукопожатия
+	TLSHandshake time.Duration
+	// TimeToFirstByte время от получения соединения до первого байта ответа:
+	// отправка запроса и обработка на сервере
+	TimeToFirstByte time.Duration
+	// ContentTransfer время получения тела ответа
+	ContentTransfer time.Duration
+	// Total полное время запроса
+	Total time.Duration
+}
+
+// timingPhase этап запроса для вывода в виде диаграммы
+type timingPhase struct {
+	name     string
+	start    time.Duration
+	duration time.Duration
+}
+
+// timingTrace собирает отметки времени этапов запроса через httptrace
+type timingTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	firstByte    time.Time
+	end          time.Time
+}
+
+// newTimingTrace создает трассировку, отсчитывающую время от текущего момента
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: time.Now()}
+}
+
+// mark записывает текущее время в отметку. При перенаправлениях отметки
+// перезаписываются, поэтому этапы относятся к последнему запросу цепочки.
+func (t *timingTrace) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+// clientTrace возвращает обработчики событий httptrace
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.mark(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { t.mark(&t.gotConn) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+// finish отмечает окончание получения ответа, повторные вызовы не меняют отметку
+func (t *timingTrace) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.end.IsZero() {
+		t.end = time.Now()
+	}
+}
+
+// phases возвращает этапы запроса, которые были выполнены, в порядке выполнения
+func (t *timingTrace) phases() []timingPhase {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var phases []timingPhase
+	add := func(name string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			phases = append(phases, timingPhase{name: name, start: from.Sub(t.start), duration: to.Sub(from)})
+		}
+	}
+	add("DNS", t.dnsStart, t.dnsDone)
+	add("TCP-подключение", t.connectStart, t.connectDone)
+	add("TLS-рукопожатие", t.tlsStart, t.tlsDone)
+	add("Ожидание ответа (TTFB)", t.gotConn, t.firstByte)
+	add("Получение тела", t.firstByte, t.endOrNow())
+	return phases
+}
+
+// timing возвращает длительности этапов запроса
+func (t *timingTrace) timing() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	end := t.endOrNow()
+	return Timing{
+		DNSLookup:       span(t.dnsStart, t.dnsDone),
+		TCPConnect:      span(t.connectStart, t.connectDone),
+		TLSHandshake:    span(t.tlsStart, t.tlsDone),
+		TimeToFirstByte: span(t.gotConn, t.firstByte),
+		ContentTransfer: span(t.firstByte, end),
+		Total:           end.Sub(t.start),
+	}
+}
+
+// endOrNow возвращает время окончания ответа или текущее время, если ответ еще не получен
+func (t *timingTrace) endOrNow() time.Time {
+	if t.end.IsZero() {
+		return time.Now()
+	}
+	return t.end
+}
+
+// span возвращает длительность между отметками или ноль, если этапа не было
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// timedBody отмечает окончание получения ответа при чтении тела до конца или закрытии
+type timedBody struct {
+	io.ReadCloser
+	trace *timingTrace
+}
+
+func (b timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.trace.finish()
+	}
+	return n, err
+}
+
+func (b timedBody) Close() error {
+	b.trace.finish()
+	return b.ReadCloser.Close()
+}
+
+// timingBarWidth ширина диаграммы этапов в символах
+const timingBarWidth = 40
+
+// printTiming выводит этапы запроса в виде таблицы с диаграммой
+func printTiming(w io.Writer, trace *timingTrace) {
+	timing := trace.timing()
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Этап", "Начало", "Длительность", ""})
+	for _, phase := range trace.phases() {
+		t.AppendRow(table.Row{phase.name, formatMillis(phase.start), formatMillis(phase.duration), timingBar(phase, timing.Total)})
+	}
+	t.AppendSeparator()
+	t.AppendRow(table.Row{"Всего", "", formatMillis(timing.Total), ""})
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+// timingBar рисует положение этапа на общей шкале времени запроса
+func timingBar(phase timingPhase, total time.Duration) string {
+	if total <= 0 {
+		return ""
+	}
+	offset := int(int64(timingBarWidth) * int64(phase.start) / int64(total))
+	length := int(int64(timingBarWidth) * int64(phase.duration) / int64(total))
+	offset = min(offset, timingBarWidth-1)
+	length = max(1, min(length, timingBarWidth-offset))
+	return strings.Repeat(" ", offset) + strings.Repeat("█", length) + strings.Repeat(" ", timingBarWidth-offset-length)
+}
+
+// printTimingJSON выводит длительности этапов запроса в формате JSON, в миллисекундах
+func printTimingJSON(w io.Writer, timing Timing) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		DNSLookup       float64 `json:"dns_lookup_ms"`
+		TCPConnect      float64 `json:"tcp_connect_ms"`
+		TLSHandshake    float64 `json:"tls_handshake_ms"`
+		TimeToFirstByte float64 `json:"time_to_first_byte_ms"`
+		ContentTransfer float64 `json:"content_transfer_ms"`
+		Total           float64 `json:"total_ms"`
+	}{
+		DNSLookup:       millis(timing.DNSLookup),
+		TCPConnect:      millis(timing.TCPConnect),
+		TLSHandshake:    millis(timing.TLSHandshake),
+		TimeToFirstByte: millis(timing.TimeToFirstByte),
+		ContentTransfer: millis(timing.ContentTransfer),
+		Total:           millis(timing.Total),
+	})
+}
+
+// millis переводит длительность в миллисекунды с точностью до микросекунды
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// formatMillis форматирует длительность в миллисекундах для таблицы
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.1f мс", millis(d))
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPResponse_Timing(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "first")
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "second")
+	}))
+	defer server.Close()
+
+	client := &HTTPClient{client: server.Client()}
+	response, body, err := client.OpenRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	_, err = io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+
+	timing := response.Timing()
+	assert.Positive(t, timing.TCPConnect)
+	assert.Positive(t, timing.TLSHandshake)
+	assert.GreaterOrEqual(t, timing.TimeToFirstByte, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, timing.ContentTransfer, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, timing.Total, timing.TCPConnect+timing.TLSHandshake+timing.TimeToFirstByte+timing.ContentTransfer)
+
+	// Время не меняется после получения ответа
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, timing, response.Timing())
+
+	var out bytes.Buffer
+	printTiming(&out, response.trace)
+	assert.Contains(t, out.String(), "TLS-рукопожатие")
+	assert.Contains(t, out.String(), "Ожидание ответа (TTFB)")
+	assert.Contains(t, out.String(), "Всего")
+	// Для IP-адреса разрешение имени не выполняется
+	assert.NotContains(t, out.String(), "DNS")
+}
+
+func TestSendRequest_Timing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	response, err := NewHTTPClient(5*time.Second).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Zero(t, response.Timing().TLSHandshake)
+	assert.Positive(t, response.Timing().Total)
+	assert.LessOrEqual(t, response.Timing().Total, response.TotalTime)
+}
+
+func TestTimingBar(t *testing.T) {
+	total := 100 * time.Millisecond
+
+	bar := timingBar(timingPhase{start: 0, duration: 50 * time.Millisecond}, total)
+	assert.Equal(t, strings.Repeat("█", 20)+strings.Repeat(" ", 20), bar)
+
+	bar = timingBar(timingPhase{start: 50 * time.Millisecond, duration: 50 * time.Millisecond}, total)
+	assert.Equal(t, strings.Repeat(" ", 20)+strings.Repeat("█", 20), bar)
+
+	// Короткий этап отображается хотя бы одним символом
+	bar = timingBar(timingPhase{start: 99 * time.Millisecond, duration: time.Microsecond}, total)
+	assert.Equal(t, strings.Repeat(" ", 39)+"█", bar)
+
+	assert.Empty(t, timingBar(timingPhase{}, 0))
+}
+
+func TestPrintTimingJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printTimingJSON(&out, Timing{
+		DNSLookup:       1500 * time.Microsecond,
+		TCPConnect:      2 * time.Millisecond,
+		TimeToFirstByte: 30 * time.Millisecond,
+		ContentTransfer: 5 * time.Millisecond,
+		Total:           38500 * time.Microsecond,
+	}))
+
+	var result map[string]float64
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, map[string]float64{
+		"dns_lookup_ms":         1.5,
+		"tcp_connect_ms":        2,
+		"tls_handshake_ms":      0,
+		"time_to_first_byte_ms": 30,
+		"content_transfer_ms":   5,
+		"total_ms":              38.5,
+	}, result)
+}
@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// TLSOptions содержит параметры TLS-соединений HTTP-клиента
+type TLSOptions struct {
+	// Insecure отключает проверку сертификата сервера
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// CACert файл с сертификатами CA в формате PEM, заменяющими системные
+	CACert string `json:"cacert,omitempty" yaml:"cacert,omitempty"`
+	// Cert и Key клиентский сертификат и закрытый ключ в формате PEM для mTLS.
+	// Если Key не задан, ключ читается из файла сертификата.
+	Cert string `json:"cert,omitempty" yaml:"cert,omitempty"`
+	Key  string `json:"key,omitempty" yaml:"key,omitempty"`
+	// MinVersion и MaxVersion допустимые версии TLS: 1.0, 1.1, 1.2, 1.3
+	MinVersion string `json:"min_version,omitempty" yaml:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty" yaml:"max_version,omitempty"`
+	// ServerName имя сервера для SNI и проверки сертификата вместо хоста из URL
+	ServerName string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+}
+
+// tlsVersions версии TLS, которые можно указать в параметрах
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// config создает конфигурацию TLS по параметрам
+func (o TLSOptions) config() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.Insecure,
+		ServerName:         o.ServerName,
+	}
+
+	if o.CACert != "" {
+		data, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения сертификатов CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("в файле %s нет сертификатов в формате PEM", o.CACert)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.Cert != "" {
+		key := o.Key
+		if key == "" {
+			key = o.Cert
+		}
+		cert, err := tls.LoadX509KeyPair(o.Cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки клиентского сертификата: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	} else if o.Key != "" {
+		return nil, fmt.Errorf("закрытый ключ задан без клиентского сертификата")
+	}
+
+	var err error
+	if config.MinVersion, config.MaxVersion, err = o.versions(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// versions возвращает константы минимальной и максимальной версий TLS, 0 для незаданных.
+// Возвращает ошибку, если версия неизвестна или минимальная больше максимальной.
+func (o TLSOptions) versions() (uint16, uint16, error) {
+	minVersion, err := parseTLSVersion(o.MinVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxVersion, err := parseTLSVersion(o.MaxVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return 0, 0, fmt.Errorf("минимальная версия TLS %s больше максимальной %s", o.MinVersion, o.MaxVersion)
+	}
+	return minVersion, maxVersion, nil
+}
+
+// parseTLSVersion возвращает константу версии TLS, 0 для пустой строки
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	if v, ok := tlsVersions[strings.TrimPrefix(version, "v")]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("неизвестная версия TLS: %s (доступны: 1.0, 1.1, 1.2, 1.3)", version)
+}
+
+// printTLS выводит параметры TLS-соединения и цепочку сертификатов сервера
+func printTLS(w io.Writer, state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "* %s, %s\n", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+	for i, cert := range state.PeerCertificates {
+		fmt.Fprintf(w, "* Сертификат %d: %s\n", i, cert.Subject)
+		fmt.Fprintf(w, "*   Издатель: %s\n", cert.Issuer)
+		fmt.Fprintf(w, "*   Действителен: %s - %s\n", cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"))
+		if names := certNames(cert); len(names) > 0 {
+			fmt.Fprintf(w, "*   Имена: %s\n", strings.Join(names, ", "))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// certNames возвращает DNS-имена и IP-адреса, для которых выдан сертификат
+func certNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
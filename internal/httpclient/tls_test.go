@@ -0,0 +1,202 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tlsServer запускает HTTPS-сервер, который возвращает CN клиентского сертификата
+func tlsServer(t *testing.T, config *tls.Config) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	}))
+	server.TLS = config
+	// Ошибки рукопожатия ожидаются в тестах и не выводятся
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeServerCA сохраняет сертификат тестового сервера в PEM-файл
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+// writeClientCert создает самоподписанный клиентский сертификат и возвращает пути
+// к сертификату и ключу и сам сертификат
+func writeClientCert(t *testing.T, commonName string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certPath, keyPath, cert
+}
+
+// newTLSClient создает клиент с заданными параметрами TLS
+func newTLSClient(t *testing.T, options TLSOptions) *HTTPClient {
+	t.Helper()
+	client, err := NewHTTPClientWithOptions(ClientOptions{Timeout: 5 * time.Second, FollowRedirects: true, MaxRedirects: 10, TLS: options})
+	require.NoError(t, err)
+	return client
+}
+
+func TestTLS_Verification(t *testing.T) {
+	server := tlsServer(t, nil)
+
+	// Самоподписанный сертификат не проходит проверку
+	_, err := NewHTTPClient(5*time.Second).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.ErrorContains(t, err, "certificate")
+
+	// Флаг insecure запроса и параметр клиента отключают проверку
+	_, err = NewHTTPClient(5*time.Second).SendRequest("GET", server.URL, nil, nil, "", "", true)
+	assert.NoError(t, err)
+	_, err = newTLSClient(t, TLSOptions{Insecure: true}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.NoError(t, err)
+
+	// Сертификат CA из файла
+	response, err := newTLSClient(t, TLSOptions{CACert: writeServerCA(t, server)}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	require.NotNil(t, response.TLS)
+	assert.Equal(t, server.Certificate().Raw, response.TLS.PeerCertificates[0].Raw)
+}
+
+func TestTLS_ServerName(t *testing.T) {
+	server := tlsServer(t, nil)
+	ca := writeServerCA(t, server)
+
+	// Сертификат тестового сервера выдан для example.com
+	response, err := newTLSClient(t, TLSOptions{CACert: ca, ServerName: "example.com"}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", response.TLS.ServerName)
+
+	_, err = newTLSClient(t, TLSOptions{CACert: ca, ServerName: "other.test"}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.ErrorContains(t, err, "other.test")
+}
+
+func TestTLS_ClientCertificate(t *testing.T) {
+	certPath, keyPath, cert := writeClientCert(t, "devhelper-client")
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	server := tlsServer(t, &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool})
+	ca := writeServerCA(t, server)
+
+	_, err := newTLSClient(t, TLSOptions{CACert: ca}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.Error(t, err)
+
+	response, err := newTLSClient(t, TLSOptions{CACert: ca, Cert: certPath, Key: keyPath}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "devhelper-client", string(response.Body))
+
+	// Сертификат и ключ в одном файле
+	combined := filepath.Join(t.TempDir(), "client-with-key.pem")
+	certPEM, _ := os.ReadFile(certPath)
+	keyPEM, _ := os.ReadFile(keyPath)
+	require.NoError(t, os.WriteFile(combined, append(certPEM, keyPEM...), 0600))
+	response, err = newTLSClient(t, TLSOptions{CACert: ca, Cert: combined}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "devhelper-client", string(response.Body))
+}
+
+func TestTLS_Versions(t *testing.T) {
+	server := tlsServer(t, &tls.Config{MaxVersion: tls.VersionTLS12})
+
+	response, err := newTLSClient(t, TLSOptions{Insecure: true}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), response.TLS.Version)
+
+	_, err = newTLSClient(t, TLSOptions{Insecure: true, MinVersion: "1.3"}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	assert.Error(t, err)
+
+	server = tlsServer(t, nil)
+	response, err = newTLSClient(t, TLSOptions{Insecure: true, MaxVersion: "1.2"}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), response.TLS.Version)
+}
+
+func TestTLSOptions_Errors(t *testing.T) {
+	certPath, _, _ := writeClientCert(t, "client")
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0644))
+
+	tests := []struct {
+		name     string
+		options  TLSOptions
+		expected string
+	}{
+		{"missing CA", TLSOptions{CACert: filepath.Join(t.TempDir(), "missing.pem")}, "ошибка чтения сертификатов CA"},
+		{"empty CA", TLSOptions{CACert: empty}, "нет сертификатов в формате PEM"},
+		{"cert without key", TLSOptions{Cert: certPath}, "ошибка загрузки клиентского сертификата"},
+		{"key without cert", TLSOptions{Key: certPath}, "закрытый ключ задан без клиентского сертификата"},
+		{"unknown version", TLSOptions{MinVersion: "1.4"}, "неизвестная версия TLS: 1.4"},
+		{"min above max", TLSOptions{MinVersion: "1.3", MaxVersion: "1.2"}, "минимальная версия TLS 1.3 больше максимальной 1.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPClientWithOptions(ClientOptions{TLS: tt.options})
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPrintTLS(t *testing.T) {
+	var out bytes.Buffer
+	printTLS(&out, nil)
+	assert.Empty(t, out.String())
+
+	server := tlsServer(t, nil)
+	response, err := newTLSClient(t, TLSOptions{CACert: writeServerCA(t, server)}).SendRequest("GET", server.URL, nil, nil, "", "", false)
+	require.NoError(t, err)
+
+	printTLS(&out, response.TLS)
+	assert.Contains(t, out.String(), "* TLS 1.3, TLS_")
+	assert.Contains(t, out.String(), "* Сертификат 0: O=Acme Co")
+	assert.Contains(t, out.String(), "*   Имена: example.com, *.example.com, 127.0.0.1, ::1")
+}
@@ -0,0 +1,402 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAlertHysteresis гистерезис правил по умолчанию (% от порога)
+const DefaultAlertHysteresis = 5.0
+
+// alertCommandTimeout время, после которого команда правила принудительно завершается,
+// чтобы зависшая команда не задерживала завершение мониторинга
+const alertCommandTimeout = 30 * time.Second
+
+// Действия, выполняемые при срабатывании правила
+const (
+	AlertActionStderr = "stderr"
+	AlertActionLog    = "log"
+	AlertActionExec   = "exec"
+)
+
+// Состояния оповещения
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// ErrAlertTriggered возвращается из Start, если сработало правило и задан режим --until-alert
+var ErrAlertTriggered = errors.New("сработало правило оповещения")
+
+// alertRulePattern разбирает выражение вида "disk:/var > 85 for 30s"
+var alertRulePattern = regexp.MustCompile(`^([a-z0-9]+)(?::(\S+))?\s*(>=|<=|>|<)\s*([0-9]+(?:\.[0-9]+)?)(?:\s+for\s+(\S+))?$`)
+
+// Thresholds определяет пороги цветовой индикации (%)
+type Thresholds struct {
+	Warning  float64 // Порог предупреждения (желтый цвет)
+	Critical float64 // Критический порог (красный цвет)
+}
+
+// DefaultThresholds пороги цветовой индикации по умолчанию
+var DefaultThresholds = Thresholds{Warning: 70, Critical: 90}
+
+// Validate проверяет пороги цветовой индикации
+func (t Thresholds) Validate() error {
+	if t.Warning < 0 || t.Critical > 100 || t.Warning > t.Critical {
+		return fmt.Errorf("неверные пороги: warning=%.1f, critical=%.1f (требуется 0 <= warning <= critical <= 100)",
+			t.Warning, t.Critical)
+	}
+	return nil
+}
+
+// AlertRule представляет правило оповещения
+type AlertRule struct {
+	Expr      string        // Исходное выражение правила
+	Metric    string        // Метрика: cpu, mem, swap, disk, inodes, load1, load5, load15, proc, cgroup
+	Arg       string        // Аргумент метрики: точка монтирования для disk/inodes, поле для proc и cgroup
+	Op        string        // Оператор сравнения: >, >=, <, <=
+	Threshold float64       // Пороговое значение
+	For       time.Duration // Сколько условие должно выполняться до срабатывания
+	Action    string        // Действие: stderr, log или exec
+	Target    string        // Файл журнала для log или команда для exec
+}
+
+// AlertEvent представляет срабатывание или снятие оповещения
+type AlertEvent struct {
+	Rule  *AlertRule
+	State string // firing или resolved
+	Value float64
+	Time  time.Time
+}
+
+// String возвращает текстовое представление события для журнала
+func (e AlertEvent) String() string {
+	return fmt.Sprintf("%s [%s] %s: %.2f",
+		e.Time.Format("2006-01-02 15:04:05"), strings.ToUpper(e.State), e.Rule.Expr, e.Value)
+}
+
+// ParseAlertRule разбирает правило оповещения.
+// Формат: "<метрика> <оператор> <порог> [for <длительность>] [then <действие>]",
+// где действие - stderr, log:<файл> или exec:<команда>.
+func ParseAlertRule(s string) (AlertRule, error) {
+	rule := AlertRule{Action: AlertActionStderr}
+
+	expr, action, hasAction := strings.Cut(s, " then ")
+	expr = strings.TrimSpace(expr)
+	rule.Expr = expr
+
+	match := alertRulePattern.FindStringSubmatch(expr)
+	if match == nil {
+		return rule, fmt.Errorf("неверный формат правила %q (пример: \"cpu > 90 for 30s\")", s)
+	}
+
+	rule.Metric, rule.Arg, rule.Op = match[1], match[2], match[3]
+	rule.Threshold, _ = strconv.ParseFloat(match[4], 64)
+
+	switch rule.Metric {
+	case "cpu", "mem", "swap", "load1", "load5", "load15":
+		if rule.Arg != "" {
+			return rule, fmt.Errorf("метрика %s не принимает аргумент: %q", rule.Metric, s)
+		}
+	case "disk":
+	case "inodes":
+		if rule.Arg == "" {
+			return rule, fmt.Errorf("для метрики inodes нужна точка монтирования, например inodes:/var: %q", s)
+		}
+	case "proc":
+		switch rule.Arg {
+		case "cpu", "mem", "rss", "threads", "fds":
+		default:
+			return rule, fmt.Errorf("неизвестное поле процесса %q (доступны: cpu, mem, rss, threads, fds)", rule.Arg)
+		}
+	case "cgroup":
+		switch rule.Arg {
+		case "cpu", "mem", "throttled":
+		default:
+			return rule, fmt.Errorf("неизвестное поле cgroup %q (доступны: cpu, mem, throttled)", rule.Arg)
+		}
+	default:
+		return rule, fmt.Errorf("неизвестная метрика %q (доступны: cpu, mem, swap, disk, inodes, load1, load5, load15, proc, cgroup)", rule.Metric)
+	}
+
+	if match[5] != "" {
+		duration, err := time.ParseDuration(match[5])
+		if err != nil || duration < 0 {
+			return rule, fmt.Errorf("неверная длительность %q в правиле %q", match[5], s)
+		}
+		rule.For = duration
+	}
+
+	if hasAction {
+		kind, target, _ := strings.Cut(strings.TrimSpace(action), ":")
+		rule.Action, rule.Target = kind, strings.TrimSpace(target)
+		switch rule.Action {
+		case AlertActionStderr:
+		case AlertActionLog, AlertActionExec:
+			if rule.Target == "" {
+				return rule, fmt.Errorf("для действия %s нужно указать %s:<значение>: %q", rule.Action, rule.Action, s)
+			}
+		default:
+			return rule, fmt.Errorf("неизвестное действие %q (доступны: stderr, log:<файл>, exec:<команда>)", rule.Action)
+		}
+	}
+
+	return rule, nil
+}
+
+// Value возвращает значение метрики правила. Второе значение false,
+// если метрика недоступна (точка монтирования не найдена, процесс не отслеживается, cgroup не учитывается).
+func (r *AlertRule) Value(stats SystemStats) (float64, bool) {
+	switch r.Metric {
+	case "cpu":
+		return stats.CPU, true
+	case "mem":
+		return stats.Memory, true
+	case "swap":
+		return stats.Swap, true
+	case "load1":
+		return stats.Load1, true
+	case "load5":
+		return stats.Load5, true
+	case "load15":
+		return stats.Load15, true
+	case "disk", "inodes":
+		if r.Arg == "" {
+			return stats.DiskUsage, true
+		}
+		mount, ok := findMount(stats.Mounts, r.Arg)
+		if !ok {
+			return 0, false
+		}
+		if r.Metric == "inodes" {
+			return mount.InodeUsage, true
+		}
+		return mount.Usage, true
+	case "proc":
+		target := stats.Target
+		if target == nil || len(target.PIDs) == 0 {
+			return 0, false
+		}
+		switch r.Arg {
+		case "cpu":
+			return target.CPU, true
+		case "mem":
+			return target.Memory, true
+		case "rss":
+			return float64(target.RSS), true
+		case "threads":
+			return float64(target.Threads), true
+		case "fds":
+			return float64(target.FDs), true
+		}
+	case "cgroup":
+		cgroup := stats.Cgroup
+		if cgroup == nil {
+			return 0, false
+		}
+		switch r.Arg {
+		case "cpu":
+			return cgroup.CPU, true
+		case "mem":
+			return cgroup.Memory, true
+		case "throttled":
+			return cgroup.ThrottledPercent, true
+		}
+	}
+	return 0, false
+}
+
+// exceeded проверяет, выполняется ли условие правила
+func (r *AlertRule) exceeded(value float64) bool {
+	switch r.Op {
+	case ">":
+		return value > r.Threshold
+	case ">=":
+		return value >= r.Threshold
+	case "<":
+		return value < r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	}
+	return false
+}
+
+// cleared проверяет, вышло ли значение за порог с учетом гистерезиса (% от порога)
+func (r *AlertRule) cleared(value, hysteresis float64) bool {
+	margin := r.Threshold * hysteresis / 100
+	if margin < 0 {
+		margin = -margin
+	}
+	switch r.Op {
+	case ">", ">=":
+		return value < r.Threshold-margin
+	default:
+		return value > r.Threshold+margin
+	}
+}
+
+// alertState хранит состояние правила между замерами
+type alertState struct {
+	rule         *AlertRule
+	pendingSince time.Time // Начало выполнения условия, нулевое если условие не выполняется
+	firing       bool
+	value        float64
+}
+
+// alertManager отслеживает состояние правил и выполняет действия при их срабатывании
+type alertManager struct {
+	states     []*alertState
+	hysteresis float64
+	stderr     io.Writer
+	commands   sync.WaitGroup // Запущенные команды правил
+	timeout    time.Duration  // Ограничение времени выполнения команды правила
+}
+
+// newAlertManager создает менеджер оповещений для списка правил
+func newAlertManager(rules []AlertRule, hysteresis float64) *alertManager {
+	manager := &alertManager{hysteresis: hysteresis, stderr: os.Stderr, timeout: alertCommandTimeout}
+	for i := range rules {
+		manager.states = append(manager.states, &alertState{rule: &rules[i]})
+	}
+	return manager
+}
+
+// Evaluate проверяет правила по очередному замеру и возвращает события срабатывания и снятия.
+// Если метрика недоступна, состояние правила не меняется, а отсчет длительности сбрасывается.
+func (a *alertManager) Evaluate(stats SystemStats, now time.Time) []AlertEvent {
+	var events []AlertEvent
+	for _, state := range a.states {
+		value, ok := state.rule.Value(stats)
+		if !ok {
+			state.pendingSince = time.Time{}
+			continue
+		}
+		state.value = value
+
+		if state.firing {
+			if state.rule.cleared(value, a.hysteresis) {
+				state.firing = false
+				state.pendingSince = time.Time{}
+				events = append(events, AlertEvent{Rule: state.rule, State: AlertResolved, Value: value, Time: now})
+			}
+			continue
+		}
+
+		if !state.rule.exceeded(value) {
+			state.pendingSince = time.Time{}
+			continue
+		}
+		if state.pendingSince.IsZero() {
+			state.pendingSince = now
+		}
+		if now.Sub(state.pendingSince) >= state.rule.For {
+			state.firing = true
+			events = append(events, AlertEvent{Rule: state.rule, State: AlertFiring, Value: value, Time: now})
+		}
+	}
+	return events
+}
+
+// Active возвращает сработавшие в данный момент правила с последними значениями
+func (a *alertManager) Active() []AlertEvent {
+	var active []AlertEvent
+	for _, state := range a.states {
+		if state.firing {
+			active = append(active, AlertEvent{Rule: state.rule, State: AlertFiring, Value: state.value})
+		}
+	}
+	return active
+}
+
+// Notify выполняет действия правил для событий.
+// Ошибки действий выводятся в stderr и не прерывают мониторинг.
+func (a *alertManager) Notify(events []AlertEvent) {
+	for _, event := range events {
+		var err error
+		switch event.Rule.Action {
+		case AlertActionLog:
+			err = appendAlertLog(event.Rule.Target, event.String())
+		case AlertActionExec:
+			err = a.runCommand(event)
+		default:
+			fmt.Fprintln(a.stderr, event.String())
+		}
+		if err != nil {
+			fmt.Fprintf(a.stderr, "Ошибка при выполнении действия правила %q: %s\n", event.Rule.Expr, err)
+		}
+	}
+}
+
+// appendAlertLog дописывает строку в файл журнала оповещений
+func appendAlertLog(path, line string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть журнал %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, line); err != nil {
+		return fmt.Errorf("не удалось записать в журнал %s: %w", path, err)
+	}
+	return nil
+}
+
+// Wait ожидает завершения запущенных команд правил
+func (a *alertManager) Wait() {
+	a.commands.Wait()
+}
+
+// runCommand запускает команду правила через командную оболочку, не дожидаясь ее завершения.
+// Параметры события передаются через переменные окружения DEVHELPER_ALERT_*.
+// Команда, не завершившаяся за время таймаута, принудительно завершается.
+func (a *alertManager) runCommand(event AlertEvent) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	// Поток вывода фиксируется при запуске: панель подменяет его из основного потока,
+	// пока команда выполняется в фоне
+	out := a.stderr
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	cmd := exec.CommandContext(ctx, shell, flag, event.Rule.Target)
+	// Если после завершения оболочки вывод удерживают ее дочерние процессы, ожидание не бесконечно
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"DEVHELPER_ALERT_RULE="+event.Rule.Expr,
+		"DEVHELPER_ALERT_STATE="+event.State,
+		"DEVHELPER_ALERT_VALUE="+strconv.FormatFloat(event.Value, 'f', 2, 64),
+		"DEVHELPER_ALERT_TIME="+event.Time.Format(time.RFC3339),
+	)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return fmt.Errorf("не удалось запустить команду: %w", err)
+	}
+	a.commands.Add(1)
+	go func() {
+		defer a.commands.Done()
+		defer cancel()
+		err := cmd.Wait()
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			fmt.Fprintf(out, "Команда правила %q прервана: превышено время выполнения %s\n", event.Rule.Expr, a.timeout)
+		case err != nil:
+			fmt.Fprintf(out, "Команда правила %q завершилась с ошибкой: %s\n", event.Rule.Expr, err)
+		}
+	}()
+	return nil
+}
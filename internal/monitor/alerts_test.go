@@ -0,0 +1,252 @@
+package monitor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    AlertRule
+		wantErr bool
+	}{
+		{
+			name:  "CPU с длительностью",
+			input: "cpu > 90 for 30s",
+			want: AlertRule{Expr: "cpu > 90 for 30s", Metric: "cpu", Op: ">", Threshold: 90,
+				For: 30 * time.Second, Action: AlertActionStderr},
+		},
+		{
+			name:  "Точка монтирования без пробелов",
+			input: "disk:/var>=85.5",
+			want:  AlertRule{Expr: "disk:/var>=85.5", Metric: "disk", Arg: "/var", Op: ">=", Threshold: 85.5, Action: AlertActionStderr},
+		},
+		{
+			name:  "Запись в журнал",
+			input: "mem > 80 then log:/tmp/alerts.log",
+			want: AlertRule{Expr: "mem > 80", Metric: "mem", Op: ">", Threshold: 80,
+				Action: AlertActionLog, Target: "/tmp/alerts.log"},
+		},
+		{
+			name:  "Команда с аргументами",
+			input: "proc:fds > 1000 for 1m then exec:notify-send 'too many fds'",
+			want: AlertRule{Expr: "proc:fds > 1000 for 1m", Metric: "proc", Arg: "fds", Op: ">", Threshold: 1000,
+				For: time.Minute, Action: AlertActionExec, Target: "notify-send 'too many fds'"},
+		},
+		{
+			name:  "Ограничение CPU контейнера",
+			input: "cgroup:throttled > 10 for 1m",
+			want: AlertRule{Expr: "cgroup:throttled > 10 for 1m", Metric: "cgroup", Arg: "throttled", Op: ">", Threshold: 10,
+				For: time.Minute, Action: AlertActionStderr},
+		},
+		{name: "Неизвестная метрика", input: "gpu > 50", wantErr: true},
+		{name: "Нет порога", input: "cpu >", wantErr: true},
+		{name: "Аргумент у cpu", input: "cpu:0 > 50", wantErr: true},
+		{name: "inodes без точки монтирования", input: "inodes > 50", wantErr: true},
+		{name: "Неизвестное поле процесса", input: "proc:io > 50", wantErr: true},
+		{name: "Неизвестное поле cgroup", input: "cgroup:swap > 50", wantErr: true},
+		{name: "Неверная длительность", input: "cpu > 50 for soon", wantErr: true},
+		{name: "Неизвестное действие", input: "cpu > 50 then mail:root", wantErr: true},
+		{name: "Пустая команда", input: "cpu > 50 then exec:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseAlertRule(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+func TestAlertRule_Value(t *testing.T) {
+	stats := SystemStats{
+		CPU: 50, Memory: 60, DiskUsage: 40, Load5: 1.5,
+		Mounts: []MountStats{{MountPoint: "/var", Usage: 90, InodeUsage: 12}},
+		Target: &TargetStats{PIDs: []int{1}, FDs: 64, RSS: 1024},
+		Cgroup: &CgroupStats{CPU: 95, Memory: 70, ThrottledPercent: 30},
+	}
+
+	tests := []struct {
+		expr  string
+		value float64
+		ok    bool
+	}{
+		{"cpu > 1", 50, true},
+		{"mem > 1", 60, true},
+		{"disk > 1", 40, true},
+		{"load5 > 1", 1.5, true},
+		{"disk:/var > 1", 90, true},
+		{"inodes:/var > 1", 12, true},
+		{"disk:/home > 1", 0, false},
+		{"proc:fds > 1", 64, true},
+		{"proc:rss > 1", 1024, true},
+		{"cgroup:cpu > 1", 95, true},
+		{"cgroup:mem > 1", 70, true},
+		{"cgroup:throttled > 1", 30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			rule, err := ParseAlertRule(tt.expr)
+			require.NoError(t, err)
+			value, ok := rule.Value(stats)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+
+	rule, err := ParseAlertRule("proc:cpu > 1")
+	require.NoError(t, err)
+	_, ok := rule.Value(SystemStats{})
+	assert.False(t, ok)
+
+	rule, err = ParseAlertRule("cgroup:mem > 1")
+	require.NoError(t, err)
+	_, ok = rule.Value(SystemStats{})
+	assert.False(t, ok)
+}
+
+func TestAlertManager_Evaluate(t *testing.T) {
+	rule, err := ParseAlertRule("cpu > 90 for 30s")
+	require.NoError(t, err)
+	manager := newAlertManager([]AlertRule{rule}, DefaultAlertHysteresis)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Условие должно выполняться 30 секунд
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 95}, start))
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 97}, start.Add(20*time.Second)))
+
+	// Кратковременное снижение сбрасывает отсчет
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 50}, start.Add(25*time.Second)))
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 95}, start.Add(30*time.Second)))
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 95}, start.Add(50*time.Second)))
+
+	events := manager.Evaluate(SystemStats{CPU: 96}, start.Add(60*time.Second))
+	require.Len(t, events, 1)
+	assert.Equal(t, AlertFiring, events[0].State)
+	assert.Equal(t, 96.0, events[0].Value)
+	assert.Len(t, manager.Active(), 1)
+
+	// Повторно не срабатывает, пока не снято
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 99}, start.Add(70*time.Second)))
+
+	// Гистерезис: 5% от 90 - снятие ниже 85.5
+	assert.Empty(t, manager.Evaluate(SystemStats{CPU: 88}, start.Add(80*time.Second)))
+	events = manager.Evaluate(SystemStats{CPU: 85}, start.Add(90*time.Second))
+	require.Len(t, events, 1)
+	assert.Equal(t, AlertResolved, events[0].State)
+	assert.Empty(t, manager.Active())
+}
+
+func TestAlertManager_EvaluateLessThan(t *testing.T) {
+	rule, err := ParseAlertRule("proc:threads < 1")
+	require.NoError(t, err)
+	manager := newAlertManager([]AlertRule{rule}, 0)
+	now := time.Now()
+
+	// Метрика недоступна - состояние не меняется
+	assert.Empty(t, manager.Evaluate(SystemStats{}, now))
+
+	target := &TargetStats{PIDs: []int{1}}
+	events := manager.Evaluate(SystemStats{Target: target}, now)
+	require.Len(t, events, 1)
+	assert.Equal(t, AlertFiring, events[0].State)
+
+	target.Threads = 2
+	events = manager.Evaluate(SystemStats{Target: target}, now)
+	require.Len(t, events, 1)
+	assert.Equal(t, AlertResolved, events[0].State)
+}
+
+func TestThresholds_Validate(t *testing.T) {
+	assert.NoError(t, DefaultThresholds.Validate())
+	assert.NoError(t, Thresholds{Warning: 50, Critical: 50}.Validate())
+	assert.Error(t, Thresholds{Warning: 95, Critical: 90}.Validate())
+	assert.Error(t, Thresholds{Warning: -1, Critical: 90}.Validate())
+	assert.Error(t, Thresholds{Warning: 70, Critical: 120}.Validate())
+}
+
+func TestAlertManager_Notify(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "alerts.log")
+
+	stderrRule, err := ParseAlertRule("cpu > 90")
+	require.NoError(t, err)
+	logRule, err := ParseAlertRule("mem > 80 then log:" + logPath)
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+	manager := newAlertManager([]AlertRule{stderrRule, logRule}, DefaultAlertHysteresis)
+	manager.stderr = &stderr
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	manager.Notify(manager.Evaluate(SystemStats{CPU: 95, Memory: 85}, now))
+	manager.Notify(manager.Evaluate(SystemStats{CPU: 95, Memory: 10}, now.Add(time.Second)))
+
+	assert.Equal(t, "2024-01-01 12:00:00 [FIRING] cpu > 90: 95.00\n", stderr.String())
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "2024-01-01 12:00:00 [FIRING] mem > 80: 85.00", lines[0])
+	assert.Equal(t, "2024-01-01 12:00:01 [RESOLVED] mem > 80: 10.00", lines[1])
+}
+
+func TestAlertManager_NotifyExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("тест использует sh")
+	}
+
+	output := filepath.Join(t.TempDir(), "out")
+	rule, err := ParseAlertRule(`load1 > 2 then exec:echo "$DEVHELPER_ALERT_STATE $DEVHELPER_ALERT_VALUE" > ` + output)
+	require.NoError(t, err)
+
+	manager := newAlertManager([]AlertRule{rule}, DefaultAlertHysteresis)
+	manager.stderr = &bytes.Buffer{}
+	manager.Notify(manager.Evaluate(SystemStats{Load1: 3.5}, time.Now()))
+	manager.Wait()
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "firing 3.50\n", string(data))
+}
+
+func TestAlertManager_NotifyExecTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("тест использует sh")
+	}
+
+	rule, err := ParseAlertRule("load1 > 2 then exec:sleep 10")
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+	manager := newAlertManager([]AlertRule{rule}, DefaultAlertHysteresis)
+	manager.stderr = &stderr
+	manager.timeout = 100 * time.Millisecond
+	manager.Notify(manager.Evaluate(SystemStats{Load1: 3.5}, time.Now()))
+
+	// Подмена потока вывода, как при запуске панели, не влияет на запущенную команду
+	manager.stderr = io.Discard
+
+	started := time.Now()
+	manager.Wait()
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.Equal(t, "Команда правила \"load1 > 2\" прервана: превышено время выполнения 100ms\n", stderr.String())
+}
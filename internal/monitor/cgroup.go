@@ -0,0 +1,288 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultCgroupFS путь к иерархии cgroup по умолчанию
+const DefaultCgroupFS = "/sys/fs/cgroup"
+
+// Режимы учета cgroup
+const (
+	CgroupAuto = "auto" // Показывать метрики cgroup, если для нее заданы ограничения
+	CgroupOn   = "on"   // Всегда показывать метрики cgroup рядом с метриками хоста
+	CgroupOff  = "off"  // Не читать cgroup
+	CgroupOnly = "only" // Заменить CPU и память хоста метриками cgroup
+)
+
+// cgroupUnlimited значения лимита памяти cgroup v1 не меньше этого считаются отсутствием лимита
+const cgroupUnlimited = 1 << 62
+
+// CgroupStats представляет использование ресурсов собственной cgroup (контейнера)
+type CgroupStats struct {
+	Version          int     `json:"version"`           // Версия cgroup: 1 или 2
+	Path             string  `json:"path"`              // Путь cgroup из /proc/self/cgroup
+	CPU              float64 `json:"cpu"`               // Использование CPU от лимита (%)
+	CPULimit         float64 `json:"cpu_limit"`         // Лимит CPU в ядрах, 0 - без лимита
+	Memory           float64 `json:"memory"`            // Использование памяти от лимита или памяти хоста (%)
+	MemoryUsed       uint64  `json:"memory_used"`       // Использовано памяти без неактивного файлового кэша (байты)
+	MemoryLimit      uint64  `json:"memory_limit"`      // Лимит памяти (байты), 0 - без лимита
+	Periods          uint64  `json:"periods"`           // Периоды планировщика CFS с момента создания
+	Throttled        uint64  `json:"throttled"`         // Периоды с ограничением CPU с момента создания
+	ThrottledTime    float64 `json:"throttled_time"`    // Время ограничения CPU с момента создания (с)
+	ThrottledPercent float64 `json:"throttled_percent"` // Доля периодов с ограничением с предыдущего замера (%)
+}
+
+// ValidateCgroupMode проверяет режим учета cgroup
+func ValidateCgroupMode(mode string) error {
+	switch mode {
+	case CgroupAuto, CgroupOn, CgroupOff, CgroupOnly:
+		return nil
+	}
+	return fmt.Errorf("неизвестный режим cgroup: %s (доступны: auto, on, off, only)", mode)
+}
+
+// cgroupInfo описывает обнаруженную cgroup процесса
+type cgroupInfo struct {
+	version   int
+	path      string
+	cpuDir    string // Каталог контроллера cpu (v1) или cgroup (v2)
+	acctDir   string // Каталог контроллера cpuacct (v1) или cgroup (v2)
+	memoryDir string // Каталог контроллера memory (v1) или cgroup (v2)
+}
+
+// cgroupCounters содержит счетчики и лимиты cgroup
+type cgroupCounters struct {
+	usage         uint64  // Процессорное время (нс)
+	periods       uint64  // nr_periods
+	throttled     uint64  // nr_throttled
+	throttledTime uint64  // Время ограничения (нс)
+	cpuLimit      float64 // Лимит в ядрах, 0 - без лимита
+	memoryUsed    uint64
+	memoryLimit   uint64 // 0 - без лимита
+}
+
+// collectCgroup собирает метрики собственной cgroup.
+// В режиме auto возвращает nil, если cgroup не найдена или для нее не заданы ограничения.
+func (c *Collector) collectCgroup(elapsed float64, hostCores int, hostMem uint64) (*CgroupStats, error) {
+	if !c.cgroupDetected {
+		c.cgroup, c.cgroupErr = detectCgroup(c.procPath("self/cgroup"), c.cgroupRoot)
+		c.cgroupDetected = true
+	}
+	if c.cgroupErr != nil {
+		if c.cgroupMode == CgroupAuto {
+			return nil, nil
+		}
+		return nil, c.cgroupErr
+	}
+
+	cur := readCgroupCounters(c.cgroup)
+	if c.cgroupMode == CgroupAuto && cur.cpuLimit == 0 && cur.memoryLimit == 0 {
+		return nil, nil
+	}
+
+	stats := &CgroupStats{
+		Version:       c.cgroup.version,
+		Path:          c.cgroup.path,
+		CPULimit:      cur.cpuLimit,
+		MemoryUsed:    cur.memoryUsed,
+		MemoryLimit:   cur.memoryLimit,
+		Periods:       cur.periods,
+		Throttled:     cur.throttled,
+		ThrottledTime: float64(cur.throttledTime) / 1e9,
+	}
+
+	if cur.memoryLimit > 0 {
+		stats.Memory = percentOf(cur.memoryUsed, cur.memoryLimit)
+	} else {
+		stats.Memory = percentOf(cur.memoryUsed, hostMem)
+	}
+
+	if c.hasPrevCgroup && elapsed > 0 {
+		prev := c.prevCgroup
+		cores := cur.cpuLimit
+		if cores == 0 {
+			cores = float64(hostCores)
+		}
+		if cores > 0 && cur.usage >= prev.usage {
+			stats.CPU = float64(cur.usage-prev.usage) / 1e9 / elapsed / cores * 100
+		}
+		if cur.periods > prev.periods && cur.throttled >= prev.throttled {
+			stats.ThrottledPercent = float64(cur.throttled-prev.throttled) / float64(cur.periods-prev.periods) * 100
+		}
+	}
+	c.prevCgroup = cur
+	c.hasPrevCgroup = true
+
+	return stats, nil
+}
+
+// detectCgroup определяет версию и каталоги cgroup процесса по /proc/self/cgroup
+func detectCgroup(selfCgroup, root string) (*cgroupInfo, error) {
+	data, err := os.ReadFile(selfCgroup)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", selfCgroup, err)
+	}
+
+	// Строки имеют вид id:контроллеры:путь, для cgroup v2 - 0::путь
+	paths := make(map[string]string)
+	unified, hasUnified := "", false
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			unified, hasUnified = parts[2], true
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+		// Каталоги совмещенных контроллеров называются по списку, например cpu,cpuacct
+		paths[parts[1]] = parts[2]
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil && hasUnified {
+		dir := resolveCgroupDir(root, unified)
+		return &cgroupInfo{version: 2, path: unified, cpuDir: dir, acctDir: dir, memoryDir: dir}, nil
+	}
+
+	info := &cgroupInfo{version: 1}
+	info.cpuDir = findCgroupV1Dir(root, paths, "cpu", "cpu,cpuacct", "cpuacct,cpu")
+	info.acctDir = findCgroupV1Dir(root, paths, "cpuacct", "cpu,cpuacct", "cpuacct,cpu")
+	info.memoryDir = findCgroupV1Dir(root, paths, "memory")
+	if info.cpuDir == "" && info.acctDir == "" && info.memoryDir == "" {
+		return nil, fmt.Errorf("cgroup не обнаружена в %s", root)
+	}
+	info.path = paths["memory"]
+	if info.path == "" {
+		info.path = paths["cpu"]
+	}
+	return info, nil
+}
+
+// findCgroupV1Dir находит каталог контроллера cgroup v1 по одному из имен
+func findCgroupV1Dir(root string, paths map[string]string, names ...string) string {
+	for _, name := range names {
+		path, ok := paths[name]
+		if !ok {
+			continue
+		}
+		base := filepath.Join(root, name)
+		if info, err := os.Stat(base); err == nil && info.IsDir() {
+			return resolveCgroupDir(base, path)
+		}
+	}
+	return ""
+}
+
+// resolveCgroupDir возвращает каталог cgroup внутри иерархии.
+// В контейнере с собственным пространством имен cgroup иерархия смонтирована
+// начиная с его cgroup, и путь из /proc/self/cgroup внутри нее не существует.
+func resolveCgroupDir(base, path string) string {
+	dir := filepath.Join(base, path)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return base
+}
+
+// readCgroupCounters читает счетчики и лимиты cgroup.
+// Отсутствующие файлы (контроллер не подключен) дают нулевые значения.
+func readCgroupCounters(info *cgroupInfo) cgroupCounters {
+	var counters cgroupCounters
+
+	if info.version == 2 {
+		stat := readCgroupKeyValues(filepath.Join(info.cpuDir, "cpu.stat"))
+		counters.usage = stat["usage_usec"] * 1000
+		counters.periods = stat["nr_periods"]
+		counters.throttled = stat["nr_throttled"]
+		counters.throttledTime = stat["throttled_usec"] * 1000
+
+		// cpu.max: "<квота> <период>" или "max <период>"
+		if fields := strings.Fields(readCgroupFile(filepath.Join(info.cpuDir, "cpu.max"))); len(fields) == 2 {
+			quota, errQuota := strconv.ParseFloat(fields[0], 64)
+			period, errPeriod := strconv.ParseFloat(fields[1], 64)
+			if errQuota == nil && errPeriod == nil && quota > 0 && period > 0 {
+				counters.cpuLimit = quota / period
+			}
+		}
+
+		usage, _ := strconv.ParseUint(readCgroupFile(filepath.Join(info.memoryDir, "memory.current")), 10, 64)
+		counters.memoryUsed = withoutInactiveFile(usage, filepath.Join(info.memoryDir, "memory.stat"), "inactive_file")
+		counters.memoryLimit, _ = strconv.ParseUint(readCgroupFile(filepath.Join(info.memoryDir, "memory.max")), 10, 64)
+		return counters
+	}
+
+	if info.cpuDir != "" {
+		stat := readCgroupKeyValues(filepath.Join(info.cpuDir, "cpu.stat"))
+		counters.periods = stat["nr_periods"]
+		counters.throttled = stat["nr_throttled"]
+		counters.throttledTime = stat["throttled_time"]
+
+		quota, errQuota := strconv.ParseFloat(readCgroupFile(filepath.Join(info.cpuDir, "cpu.cfs_quota_us")), 64)
+		period, errPeriod := strconv.ParseFloat(readCgroupFile(filepath.Join(info.cpuDir, "cpu.cfs_period_us")), 64)
+		if errQuota == nil && errPeriod == nil && quota > 0 && period > 0 {
+			counters.cpuLimit = quota / period
+		}
+	}
+	if info.acctDir != "" {
+		counters.usage, _ = strconv.ParseUint(readCgroupFile(filepath.Join(info.acctDir, "cpuacct.usage")), 10, 64)
+	}
+	if info.memoryDir != "" {
+		usage, _ := strconv.ParseUint(readCgroupFile(filepath.Join(info.memoryDir, "memory.usage_in_bytes")), 10, 64)
+		counters.memoryUsed = withoutInactiveFile(usage, filepath.Join(info.memoryDir, "memory.stat"), "total_inactive_file")
+		limit, _ := strconv.ParseUint(readCgroupFile(filepath.Join(info.memoryDir, "memory.limit_in_bytes")), 10, 64)
+		if limit < cgroupUnlimited {
+			counters.memoryLimit = limit
+		}
+	}
+	return counters
+}
+
+// withoutInactiveFile вычитает из использования памяти неактивный файловый кэш,
+// который ядро вытеснит при достижении лимита (так же считает docker stats)
+func withoutInactiveFile(usage uint64, statPath, key string) uint64 {
+	if inactive := readCgroupKeyValues(statPath)[key]; inactive < usage {
+		return usage - inactive
+	}
+	return usage
+}
+
+// readCgroupFile читает однострочный файл cgroup, пустая строка при ошибке
+func readCgroupFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readCgroupKeyValues читает файл вида "ключ значение" (cpu.stat)
+func readCgroupKeyValues(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values
+}
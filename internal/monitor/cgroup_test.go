@@ -0,0 +1,186 @@
+package monitor
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCgroupV2 создает тестовую иерархию cgroup v2 с лимитом в 2 ядра и 1 ГиБ
+func writeCgroupV2(t *testing.T, procRoot, cgroupRoot string) {
+	t.Helper()
+	writeProcFile(t, procRoot, "self/cgroup", "0::/docker/abc\n")
+	writeProcFile(t, cgroupRoot, "cgroup.controllers", "cpu memory\n")
+	writeProcFile(t, cgroupRoot, "docker/abc/cpu.max", "200000 100000\n")
+	writeProcFile(t, cgroupRoot, "docker/abc/cpu.stat", "usage_usec 1000000\nnr_periods 100\nnr_throttled 10\nthrottled_usec 500000\n")
+	writeProcFile(t, cgroupRoot, "docker/abc/memory.current", "268435456\n")
+	writeProcFile(t, cgroupRoot, "docker/abc/memory.max", "1073741824\n")
+}
+
+func TestDetectCgroup_V2(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeCgroupV2(t, procRoot, cgroupRoot)
+
+	info, err := detectCgroup(filepath.Join(procRoot, "self/cgroup"), cgroupRoot)
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.version)
+	assert.Equal(t, "/docker/abc", info.path)
+	assert.Equal(t, filepath.Join(cgroupRoot, "docker/abc"), info.cpuDir)
+	assert.Equal(t, info.cpuDir, info.memoryDir)
+
+	// В собственном пространстве имен cgroup путь внутри иерархии не существует
+	namespaced := t.TempDir()
+	writeProcFile(t, namespaced, "cgroup.controllers", "cpu memory\n")
+	info, err = detectCgroup(filepath.Join(procRoot, "self/cgroup"), namespaced)
+	require.NoError(t, err)
+	assert.Equal(t, namespaced, info.cpuDir)
+}
+
+func TestDetectCgroup_V1(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeProcFile(t, procRoot, "self/cgroup", "5:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n1:name=systemd:/docker/abc\n0::/\n")
+	writeProcFile(t, cgroupRoot, "cpu,cpuacct/docker/abc/cpu.cfs_quota_us", "50000\n")
+	writeProcFile(t, cgroupRoot, "memory/docker/abc/memory.usage_in_bytes", "104857600\n")
+
+	info, err := detectCgroup(filepath.Join(procRoot, "self/cgroup"), cgroupRoot)
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.version)
+	assert.Equal(t, "/docker/abc", info.path)
+	assert.Equal(t, filepath.Join(cgroupRoot, "cpu,cpuacct/docker/abc"), info.cpuDir)
+	assert.Equal(t, filepath.Join(cgroupRoot, "cpu,cpuacct/docker/abc"), info.acctDir)
+	assert.Equal(t, filepath.Join(cgroupRoot, "memory/docker/abc"), info.memoryDir)
+
+	// Нет ни одного контроллера
+	_, err = detectCgroup(filepath.Join(procRoot, "self/cgroup"), t.TempDir())
+	assert.Error(t, err)
+
+	// Нет /proc/self/cgroup
+	_, err = detectCgroup(filepath.Join(t.TempDir(), "self/cgroup"), cgroupRoot)
+	assert.Error(t, err)
+}
+
+func TestReadCgroupCounters_V1(t *testing.T) {
+	dir := t.TempDir()
+	writeProcFile(t, dir, "cpu/cpu.cfs_quota_us", "150000\n")
+	writeProcFile(t, dir, "cpu/cpu.cfs_period_us", "100000\n")
+	writeProcFile(t, dir, "cpu/cpu.stat", "nr_periods 40\nnr_throttled 4\nthrottled_time 2000000000\n")
+	writeProcFile(t, dir, "cpuacct/cpuacct.usage", "3000000000\n")
+	writeProcFile(t, dir, "memory/memory.usage_in_bytes", "115343360\n")
+	writeProcFile(t, dir, "memory/memory.stat", "cache 20971520\ntotal_inactive_file 10485760\n")
+	writeProcFile(t, dir, "memory/memory.limit_in_bytes", "9223372036854771712\n")
+
+	counters := readCgroupCounters(&cgroupInfo{
+		version:   1,
+		cpuDir:    filepath.Join(dir, "cpu"),
+		acctDir:   filepath.Join(dir, "cpuacct"),
+		memoryDir: filepath.Join(dir, "memory"),
+	})
+	assert.Equal(t, cgroupCounters{
+		usage:         3000000000,
+		periods:       40,
+		throttled:     4,
+		throttledTime: 2000000000,
+		cpuLimit:      1.5,
+		memoryUsed:    104857600,
+		memoryLimit:   0, // Значение близкое к максимуму означает отсутствие лимита
+	}, counters)
+
+	// Квота -1 означает отсутствие лимита CPU
+	writeProcFile(t, dir, "cpu/cpu.cfs_quota_us", "-1\n")
+	counters = readCgroupCounters(&cgroupInfo{version: 1, cpuDir: filepath.Join(dir, "cpu")})
+	assert.Equal(t, 0.0, counters.cpuLimit)
+}
+
+func TestCollector_CollectCgroup(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	writeCgroupV2(t, procRoot, cgroupRoot)
+	dir := filepath.Join(cgroupRoot, "docker/abc")
+
+	collector := NewCollector(Options{ProcFS: procRoot, CgroupFS: cgroupRoot})
+
+	// Первый замер - скорости равны нулю
+	stats, err := collector.collectCgroup(0, 4, 8<<30)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 2, stats.Version)
+	assert.Equal(t, "/docker/abc", stats.Path)
+	assert.Equal(t, 2.0, stats.CPULimit)
+	assert.Equal(t, 0.0, stats.CPU)
+	assert.Equal(t, uint64(256<<20), stats.MemoryUsed)
+	assert.Equal(t, uint64(1<<30), stats.MemoryLimit)
+	assert.InDelta(t, 25.0, stats.Memory, 0.001)
+	assert.Equal(t, uint64(10), stats.Throttled)
+	assert.InDelta(t, 0.5, stats.ThrottledTime, 0.001)
+
+	// За 2 секунды израсходовано 3 секунды CPU из 4 доступных
+	writeProcFile(t, dir, "cpu.stat", "usage_usec 4000000\nnr_periods 120\nnr_throttled 15\nthrottled_usec 900000\n")
+	stats, err = collector.collectCgroup(2, 4, 8<<30)
+	require.NoError(t, err)
+	assert.InDelta(t, 75.0, stats.CPU, 0.001)
+	assert.InDelta(t, 25.0, stats.ThrottledPercent, 0.001)
+
+	// Без лимитов в режиме auto cgroup не показывается, а в режиме on - считается от ресурсов хоста
+	writeProcFile(t, dir, "cpu.max", "max 100000\n")
+	writeProcFile(t, dir, "memory.max", "max\n")
+	stats, err = collector.collectCgroup(2, 4, 8<<30)
+	require.NoError(t, err)
+	assert.Nil(t, stats)
+
+	collector.cgroupMode = CgroupOn
+	writeProcFile(t, dir, "cpu.stat", "usage_usec 8000000\nnr_periods 120\nnr_throttled 15\nthrottled_usec 900000\n")
+	stats, err = collector.collectCgroup(2, 4, 8<<30)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 0.0, stats.CPULimit)
+	assert.InDelta(t, 50.0, stats.CPU, 0.001)
+	assert.InDelta(t, 3.125, stats.Memory, 0.001)
+}
+
+func TestCollector_CollectCgroupNotFound(t *testing.T) {
+	// В режиме auto отсутствие cgroup не является ошибкой
+	collector := NewCollector(Options{ProcFS: t.TempDir(), CgroupFS: t.TempDir()})
+	stats, err := collector.collectCgroup(0, 4, 8<<30)
+	require.NoError(t, err)
+	assert.Nil(t, stats)
+
+	collector = NewCollector(Options{ProcFS: t.TempDir(), CgroupFS: t.TempDir(), Cgroup: CgroupOn})
+	_, err = collector.collectCgroup(0, 4, 8<<30)
+	assert.Error(t, err)
+}
+
+func TestCollector_CollectCgroupOnly(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	root, cgroupRoot := t.TempDir(), t.TempDir()
+	writeProcFile(t, root, "stat", "cpu  100 0 100 700 100 0 0 0 0 0\ncpu0 100 0 100 700 100 0 0 0 0 0\n")
+	writeProcFile(t, root, "meminfo", testMemInfo)
+	writeProcFile(t, root, "loadavg", "0.50 1.25 2.00 1/100 1234\n")
+	writeProcFile(t, root, "self/mounts", "")
+	writeProcFile(t, root, "diskstats", "")
+	writeProcFile(t, root, "net/dev", testNetDev("1000 10 0 0 0 0 0 0 500 5 0 0 0 0 0 0"))
+	writeCgroupV2(t, root, cgroupRoot)
+
+	collector := NewCollector(Options{ProcFS: root, DiskPath: root, CgroupFS: cgroupRoot, Cgroup: CgroupOnly})
+	collector.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	stats, err := collector.Collect()
+	require.NoError(t, err)
+	require.NotNil(t, stats.Cgroup)
+	assert.Equal(t, 0.0, stats.CPU)
+	assert.Equal(t, uint64(256<<20), stats.UsedMem)
+	assert.Equal(t, uint64(1<<30), stats.TotalMem)
+	assert.InDelta(t, 25.0, stats.Memory, 0.001)
+}
+
+func TestValidateCgroupMode(t *testing.T) {
+	for _, mode := range []string{CgroupAuto, CgroupOn, CgroupOff, CgroupOnly} {
+		assert.NoError(t, ValidateCgroupMode(mode))
+	}
+	assert.Error(t, ValidateCgroupMode("always"))
+}
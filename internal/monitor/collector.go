@@ -0,0 +1,399 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultProcFS путь к procfs по умолчанию
+const DefaultProcFS = "/proc"
+
+// DefaultDiskPath путь, для которого собирается статистика диска по умолчанию
+const DefaultDiskPath = "/"
+
+// cpuTimes содержит счетчики времени CPU из /proc/stat (в тиках)
+type cpuTimes struct {
+	total uint64
+	idle  uint64
+}
+
+// procStat содержит разобранное содержимое /proc/stat
+type procStat struct {
+	cpu   cpuTimes   // Суммарные счетчики CPU
+	cores []cpuTimes // Счетчики отдельных ядер
+	ctxt  uint64     // Переключения контекста с момента загрузки
+	intr  uint64     // Прерывания с момента загрузки
+}
+
+// fsUsage содержит информацию об использовании файловой системы
+type fsUsage struct {
+	Total     uint64 // Всего байт
+	Free      uint64 // Свободно байт (включая зарезервированные)
+	Avail     uint64 // Доступно байт непривилегированному пользователю
+	Files     uint64 // Всего inode
+	FilesFree uint64 // Свободно inode
+}
+
+// Collector собирает системные метрики из procfs и statfs
+type Collector struct {
+	procRoot        string
+	diskPath        string
+	mountFilter     MountFilter
+	processes       ProcessOptions
+	target          TargetOptions
+	cgroupRoot      string
+	cgroupMode      string
+	cgroup          *cgroupInfo
+	cgroupErr       error
+	cgroupDetected  bool
+	now             func() time.Time
+	prev            procStat
+	prevDisk        map[string]diskCounters
+	prevNet         map[string]netCounters
+	prevProc        map[int]uint64
+	prevTargetTicks map[int]uint64
+	prevTargetIO    map[int]processIO
+	prevCgroup      cgroupCounters
+	prevTime        time.Time
+	hasPrev         bool
+	hasPrevCgroup   bool
+}
+
+// NewCollector создает новый сборщик метрик.
+// Пустые пути в opts заменяются значениями по умолчанию, а если список
+// исключаемых типов ФС не задан, используется DefaultExcludeFSTypes.
+func NewCollector(opts Options) *Collector {
+	procRoot := opts.ProcFS
+	if procRoot == "" {
+		procRoot = DefaultProcFS
+	}
+	diskPath := opts.DiskPath
+	if diskPath == "" {
+		diskPath = DefaultDiskPath
+	}
+	mountFilter := opts.Mounts
+	if mountFilter.ExcludeFSTypes == nil {
+		mountFilter.ExcludeFSTypes = DefaultExcludeFSTypes
+	}
+	cgroupRoot := opts.CgroupFS
+	if cgroupRoot == "" {
+		cgroupRoot = DefaultCgroupFS
+	}
+	cgroupMode := opts.Cgroup
+	if cgroupMode == "" {
+		cgroupMode = CgroupAuto
+	}
+	return &Collector{
+		procRoot:    procRoot,
+		diskPath:    diskPath,
+		mountFilter: mountFilter,
+		processes:   opts.Processes,
+		target:      opts.Target,
+		cgroupRoot:  cgroupRoot,
+		cgroupMode:  cgroupMode,
+		now:         time.Now,
+	}
+}
+
+// Collect собирает текущую статистику системных ресурсов.
+// Загрузка CPU считается как разница со значениями предыдущего вызова,
+// при первом вызове - как среднее с момента загрузки системы.
+// Частоты событий (переключения контекста, прерывания) и скорости
+// ввода-вывода при первом вызове равны нулю.
+func (c *Collector) Collect() (SystemStats, error) {
+	var stats SystemStats
+
+	now := c.now()
+	stats.Time = now
+	stat, err := c.readStat()
+	if err != nil {
+		return stats, err
+	}
+
+	// Время с предыдущего замера в секундах и число тиков одного ядра
+	// за это время, 0 при первом вызове
+	elapsed := 0.0
+	coreTicks := 0.0
+	prev := procStat{cores: make([]cpuTimes, len(stat.cores))}
+	if c.hasPrev {
+		prev = c.prev
+		elapsed = now.Sub(c.prevTime).Seconds()
+		stats.ContextSwitches = counterRate(prev.ctxt, stat.ctxt, elapsed)
+		stats.Interrupts = counterRate(prev.intr, stat.intr, elapsed)
+		if stat.cpu.total > prev.cpu.total && len(stat.cores) > 0 {
+			coreTicks = float64(stat.cpu.total-prev.cpu.total) / float64(len(stat.cores))
+		}
+	}
+	stats.CPU = cpuPercent(prev.cpu, stat.cpu)
+	stats.Cores = make([]float64, len(stat.cores))
+	for i, core := range stat.cores {
+		// Количество ядер может измениться (hotplug), такие ядра считаем с нуля
+		var prevCore cpuTimes
+		if i < len(prev.cores) {
+			prevCore = prev.cores[i]
+		}
+		stats.Cores[i] = cpuPercent(prevCore, core)
+	}
+	c.prev = stat
+	c.prevTime = now
+	c.hasPrev = true
+
+	stats.Load1, stats.Load5, stats.Load15, err = readLoadAvg(c.procPath("loadavg"))
+	if err != nil {
+		return stats, err
+	}
+
+	meminfo, err := readMemInfo(c.procPath("meminfo"))
+	if err != nil {
+		return stats, err
+	}
+	stats.TotalMem = meminfo["MemTotal"]
+	available, ok := meminfo["MemAvailable"]
+	if !ok {
+		// Старые ядра не сообщают MemAvailable
+		available = meminfo["MemFree"] + meminfo["Buffers"] + meminfo["Cached"]
+	}
+	if available <= stats.TotalMem {
+		stats.UsedMem = stats.TotalMem - available
+	}
+	stats.Memory = percentOf(stats.UsedMem, stats.TotalMem)
+
+	stats.TotalSwap = meminfo["SwapTotal"]
+	if free := meminfo["SwapFree"]; free <= stats.TotalSwap {
+		stats.UsedSwap = stats.TotalSwap - free
+	}
+	stats.Swap = percentOf(stats.UsedSwap, stats.TotalSwap)
+
+	if c.cgroupMode != CgroupOff {
+		stats.Cgroup, err = c.collectCgroup(elapsed, len(stat.cores), stats.TotalMem)
+		if err != nil {
+			return stats, err
+		}
+		// В режиме only CPU и память хоста заменяются метриками контейнера
+		if stats.Cgroup != nil && c.cgroupMode == CgroupOnly {
+			stats.CPU = stats.Cgroup.CPU
+			stats.UsedMem = stats.Cgroup.MemoryUsed
+			if stats.Cgroup.MemoryLimit > 0 {
+				stats.TotalMem = stats.Cgroup.MemoryLimit
+			}
+			stats.Memory = stats.Cgroup.Memory
+		}
+	}
+
+	disk, err := statFS(c.diskPath)
+	if err != nil {
+		return stats, fmt.Errorf("не удалось получить статистику диска %s: %w", c.diskPath, err)
+	}
+	stats.TotalDisk = disk.Total
+	stats.UsedDisk = disk.Total - disk.Free
+	stats.DiskUsage = percentOf(stats.UsedDisk, stats.UsedDisk+disk.Avail)
+
+	stats.Mounts, err = c.collectMounts()
+	if err != nil {
+		return stats, err
+	}
+
+	stats.DiskIO, err = c.collectDiskIO(elapsed)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.NetIO, err = c.collectNetIO(elapsed)
+	if err != nil {
+		return stats, err
+	}
+
+	if c.processes.Top > 0 || c.target.Enabled() {
+		infos, err := c.readProcesses()
+		if err != nil {
+			return stats, err
+		}
+		if c.processes.Top > 0 {
+			stats.Processes = c.collectProcesses(infos, coreTicks, stats.TotalMem)
+		}
+		if c.target.Enabled() {
+			stats.Target = c.collectTarget(infos, coreTicks, elapsed, stats.TotalMem)
+		}
+	}
+
+	return stats, nil
+}
+
+// procPath возвращает путь к файлу внутри procfs
+func (c *Collector) procPath(name string) string {
+	return filepath.Join(c.procRoot, name)
+}
+
+// readStat читает счетчики CPU, переключений контекста и прерываний из /proc/stat
+func (c *Collector) readStat() (procStat, error) {
+	path := c.procPath("stat")
+	file, err := os.Open(path)
+	if err != nil {
+		return procStat{}, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var stat procStat
+	found := false
+	scanner := bufio.NewScanner(file)
+	// Строка intr может быть очень длинной на машинах с большим числом IRQ
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch {
+		case fields[0] == "cpu":
+			if stat.cpu, err = parseCPUTimes(fields[1:]); err != nil {
+				return procStat{}, err
+			}
+			found = true
+		case strings.HasPrefix(fields[0], "cpu"):
+			core, err := parseCPUTimes(fields[1:])
+			if err != nil {
+				return procStat{}, err
+			}
+			stat.cores = append(stat.cores, core)
+		case fields[0] == "ctxt":
+			stat.ctxt, _ = strconv.ParseUint(fields[1], 10, 64)
+		case fields[0] == "intr":
+			// Первое значение - общее число прерываний
+			stat.intr, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return procStat{}, fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+	if !found {
+		return procStat{}, fmt.Errorf("в %s не найдена строка cpu", path)
+	}
+
+	return stat, nil
+}
+
+// parseCPUTimes разбирает значения строки cpu из /proc/stat:
+// user nice system idle iowait irq softirq steal guest guest_nice
+func parseCPUTimes(fields []string) (cpuTimes, error) {
+	if len(fields) < 4 {
+		return cpuTimes{}, fmt.Errorf("неверный формат строки cpu: %q", strings.Join(fields, " "))
+	}
+
+	var times cpuTimes
+	for i, field := range fields {
+		// guest и guest_nice уже учтены в user и nice
+		if i >= 8 {
+			break
+		}
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuTimes{}, fmt.Errorf("неверное значение счетчика CPU %q: %w", field, err)
+		}
+		times.total += value
+		// idle и iowait
+		if i == 3 || i == 4 {
+			times.idle += value
+		}
+	}
+
+	return times, nil
+}
+
+// cpuPercent вычисляет загрузку CPU между двумя замерами
+func cpuPercent(prev, cur cpuTimes) float64 {
+	if cur.total <= prev.total {
+		return 0
+	}
+	total := cur.total - prev.total
+	idle := uint64(0)
+	if cur.idle > prev.idle {
+		idle = cur.idle - prev.idle
+	}
+	if idle > total {
+		return 0
+	}
+	return float64(total-idle) / float64(total) * 100
+}
+
+// counterRate вычисляет скорость изменения счетчика в секунду
+func counterRate(prev, cur uint64, elapsed float64) float64 {
+	if cur < prev || elapsed <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / elapsed
+}
+
+// readLoadAvg читает среднюю загрузку за 1, 5 и 15 минут из /proc/loadavg
+func readLoadAvg(path string) (load1, load5, load15 float64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("неверный формат %s", path)
+	}
+
+	values := make([]float64, 3)
+	for i := range values {
+		values[i], err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("неверное значение в %s: %w", path, err)
+		}
+	}
+
+	return values[0], values[1], values[2], nil
+}
+
+// readMemInfo читает /proc/meminfo и возвращает значения в байтах
+func readMemInfo(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+
+	if _, ok := values["MemTotal"]; !ok {
+		return nil, fmt.Errorf("в %s не найдено поле MemTotal", path)
+	}
+
+	return values, nil
+}
+
+// percentOf возвращает долю used от total в процентах
+func percentOf(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
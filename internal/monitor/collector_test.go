@@ -0,0 +1,227 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMemInfo = `MemTotal:        8000000 kB
+MemFree:         1000000 kB
+MemAvailable:    6000000 kB
+Buffers:          100000 kB
+Cached:          2000000 kB
+SwapTotal:       4000000 kB
+SwapFree:        3000000 kB
+`
+
+// writeProcFile создает файл внутри тестового дерева procfs
+func writeProcFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestCollector_Collect(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	root := t.TempDir()
+	writeProcFile(t, root, "stat", "cpu  100 0 100 700 100 0 0 0 0 0\n"+
+		"cpu0 50 0 50 400 0 0 0 0 0 0\n"+
+		"cpu1 50 0 50 300 100 0 0 0 0 0\n"+
+		"intr 1000 10 20\nctxt 5000\n")
+	writeProcFile(t, root, "meminfo", testMemInfo)
+	writeProcFile(t, root, "loadavg", "0.50 1.25 2.00 1/100 1234\n")
+	writeProcFile(t, root, "self/mounts", "/dev/sda1 "+root+" ext4 rw,relatime 0 0\n")
+	writeProcFile(t, root, "diskstats", "   8       0 sda 100 0 2000 0 50 0 1000 0 0 0 0\n")
+	writeProcFile(t, root, "net/dev", testNetDev("1000 10 0 0 0 0 0 0 500 5 0 0 0 0 0 0"))
+
+	collector := NewCollector(Options{ProcFS: root, DiskPath: root})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+
+	// Первый замер - среднее с момента загрузки
+	stats, err := collector.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, now, stats.Time)
+	assert.InDelta(t, 20.0, stats.CPU, 0.001)
+	require.Len(t, stats.Cores, 2)
+	assert.InDelta(t, 20.0, stats.Cores[0], 0.001)
+	assert.InDelta(t, 20.0, stats.Cores[1], 0.001)
+	assert.Equal(t, 0.0, stats.ContextSwitches)
+	assert.Equal(t, 0.0, stats.Interrupts)
+
+	assert.Equal(t, 0.5, stats.Load1)
+	assert.Equal(t, 1.25, stats.Load5)
+	assert.Equal(t, 2.0, stats.Load15)
+
+	assert.Equal(t, uint64(8000000*1024), stats.TotalMem)
+	assert.Equal(t, uint64(2000000*1024), stats.UsedMem)
+	assert.InDelta(t, 25.0, stats.Memory, 0.001)
+
+	assert.Equal(t, uint64(4000000*1024), stats.TotalSwap)
+	assert.Equal(t, uint64(1000000*1024), stats.UsedSwap)
+	assert.InDelta(t, 25.0, stats.Swap, 0.001)
+
+	assert.Greater(t, stats.TotalDisk, uint64(0))
+	assert.LessOrEqual(t, stats.UsedDisk, stats.TotalDisk)
+
+	require.Len(t, stats.Mounts, 1)
+	assert.Equal(t, root, stats.Mounts[0].MountPoint)
+	assert.Equal(t, stats.TotalDisk, stats.Mounts[0].Total)
+
+	assert.Equal(t, []DiskIOStats{{Device: "sda"}}, stats.DiskIO)
+	assert.Equal(t, []NetIOStats{{Interface: "eth0"}}, stats.NetIO)
+
+	// Второй замер - разница со значениями предыдущего
+	writeProcFile(t, root, "stat", "cpu  250 0 150 750 150 0 0 0 0 0\n"+
+		"cpu0 150 0 100 400 0 0 0 0 0 0\n"+
+		"cpu1 100 0 50 350 150 0 0 0 0 0\n"+
+		"intr 1400 10 20\nctxt 6000\n")
+	writeProcFile(t, root, "diskstats", "   8       0 sda 300 0 6000 0 150 0 3000 0 0 0 0\n")
+	writeProcFile(t, root, "net/dev", testNetDev("5000 10 2 0 0 0 0 0 2500 5 1 0 0 0 0 0"))
+	now = now.Add(2 * time.Second)
+	stats, err = collector.Collect()
+	require.NoError(t, err)
+	assert.InDelta(t, 66.667, stats.CPU, 0.001)
+	require.Len(t, stats.Cores, 2)
+	assert.InDelta(t, 100.0, stats.Cores[0], 0.001)
+	assert.InDelta(t, 33.333, stats.Cores[1], 0.001)
+	assert.InDelta(t, 500.0, stats.ContextSwitches, 0.001)
+	assert.InDelta(t, 200.0, stats.Interrupts, 0.001)
+
+	require.Len(t, stats.DiskIO, 1)
+	assert.InDelta(t, 2000.0*sectorSize, stats.DiskIO[0].ReadBytes, 0.001)
+	assert.InDelta(t, 1000.0*sectorSize, stats.DiskIO[0].WriteBytes, 0.001)
+	assert.InDelta(t, 100.0, stats.DiskIO[0].ReadOps, 0.001)
+	assert.InDelta(t, 50.0, stats.DiskIO[0].WriteOps, 0.001)
+
+	require.Len(t, stats.NetIO, 1)
+	assert.InDelta(t, 2000.0, stats.NetIO[0].RxBytes, 0.001)
+	assert.InDelta(t, 1000.0, stats.NetIO[0].TxBytes, 0.001)
+	assert.Equal(t, uint64(2), stats.NetIO[0].RxErrors)
+	assert.Equal(t, uint64(1), stats.NetIO[0].TxErrors)
+}
+
+func TestCollector_Errors(t *testing.T) {
+	root := t.TempDir()
+
+	// Нет файлов procfs
+	_, err := NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+	assert.Error(t, err)
+
+	// Нет строки cpu
+	writeProcFile(t, root, "stat", "intr 0\n")
+	_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+	assert.Error(t, err)
+
+	// Нет loadavg
+	writeProcFile(t, root, "stat", "cpu  1 2 3 4\n")
+	_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+	assert.Error(t, err)
+
+	// Нет meminfo
+	writeProcFile(t, root, "loadavg", "0.00 0.00 0.00 1/1 1\n")
+	_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+	assert.Error(t, err)
+
+	if runtime.GOOS == "linux" {
+		// Нет списка точек монтирования
+		writeProcFile(t, root, "meminfo", testMemInfo)
+		_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+		assert.Error(t, err)
+
+		// Нет diskstats
+		writeProcFile(t, root, "self/mounts", "")
+		_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+		assert.Error(t, err)
+
+		// Нет net/dev
+		writeProcFile(t, root, "diskstats", "")
+		_, err = NewCollector(Options{ProcFS: root, DiskPath: root}).Collect()
+		assert.Error(t, err)
+	}
+}
+
+func TestNewCollector_Defaults(t *testing.T) {
+	collector := NewCollector(Options{})
+	assert.Equal(t, DefaultProcFS, collector.procRoot)
+	assert.Equal(t, DefaultDiskPath, collector.diskPath)
+	assert.Equal(t, DefaultExcludeFSTypes, collector.mountFilter.ExcludeFSTypes)
+	assert.Equal(t, DefaultCgroupFS, collector.cgroupRoot)
+	assert.Equal(t, CgroupAuto, collector.cgroupMode)
+
+	// Явно заданный пустой список не заменяется значениями по умолчанию
+	collector = NewCollector(Options{Mounts: MountFilter{ExcludeFSTypes: []string{}}})
+	assert.Empty(t, collector.mountFilter.ExcludeFSTypes)
+}
+
+func TestParseCPUTimes(t *testing.T) {
+	times, err := parseCPUTimes([]string{"10", "20", "30", "40", "50", "60", "70", "80", "90", "100"})
+	require.NoError(t, err)
+	// guest и guest_nice не учитываются повторно
+	assert.Equal(t, uint64(360), times.total)
+	assert.Equal(t, uint64(90), times.idle)
+
+	_, err = parseCPUTimes([]string{"1", "2"})
+	assert.Error(t, err)
+
+	_, err = parseCPUTimes([]string{"1", "2", "x", "4"})
+	assert.Error(t, err)
+}
+
+func TestCPUPercent(t *testing.T) {
+	assert.Equal(t, 0.0, cpuPercent(cpuTimes{total: 100}, cpuTimes{total: 100}))
+	assert.Equal(t, 50.0, cpuPercent(cpuTimes{total: 100, idle: 50}, cpuTimes{total: 200, idle: 100}))
+	assert.Equal(t, 100.0, cpuPercent(cpuTimes{}, cpuTimes{total: 10}))
+}
+
+func TestReadLoadAvg(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "loadavg")
+
+	writeProcFile(t, root, "loadavg", "0.28 0.44 0.26 1/72 8761\n")
+	load1, load5, load15, err := readLoadAvg(path)
+	require.NoError(t, err)
+	assert.Equal(t, 0.28, load1)
+	assert.Equal(t, 0.44, load5)
+	assert.Equal(t, 0.26, load15)
+
+	writeProcFile(t, root, "loadavg", "0.28\n")
+	_, _, _, err = readLoadAvg(path)
+	assert.Error(t, err)
+
+	writeProcFile(t, root, "loadavg", "a b c\n")
+	_, _, _, err = readLoadAvg(path)
+	assert.Error(t, err)
+}
+
+func TestCounterRate(t *testing.T) {
+	assert.Equal(t, 50.0, counterRate(100, 200, 2))
+	// Сброс счетчика не дает отрицательной скорости
+	assert.Equal(t, 0.0, counterRate(200, 100, 2))
+	assert.Equal(t, 0.0, counterRate(100, 200, 0))
+}
+
+func TestReadMemInfo(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "meminfo", "MemTotal: 1024 kB\nMemFree: 512 kB\nHugePages_Total: 0\n")
+
+	values, err := readMemInfo(filepath.Join(root, "meminfo"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1024*1024), values["MemTotal"])
+	assert.Equal(t, uint64(512*1024), values["MemFree"])
+	assert.Equal(t, uint64(0), values["HugePages_Total"])
+
+	writeProcFile(t, root, "meminfo", "MemFree: 512 kB\n")
+	_, err = readMemInfo(filepath.Join(root, "meminfo"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,644 @@
+package monitor
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/term"
+)
+
+// displayDashboard отображает статистику в виде интерактивной панели
+func (m *Monitor) displayDashboard(stats SystemStats) {
+	// Очищаем экран
+	fmt.Fprint(m.writer, "\033[H\033[2J")
+
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width = 80 // Используем значение по умолчанию
+	}
+
+	// Выводим время
+	currentTime := stats.Time.Format("15:04:05")
+	fmt.Fprintf(m.writer, "\n %s | DevHelper System Monitor\n\n", currentTime)
+
+	// Ширина полос прогресса и спарклайнов зависит от ширины терминала
+	barWidth, sparkWidth := width/3, 0
+	if m.history != nil {
+		barWidth = width / 5
+		sparkWidth = min(m.history.size, width/5)
+	}
+
+	// withHistory добавляет колонку истории, если она включена
+	withHistory := func(row table.Row, spark string) table.Row {
+		if m.history == nil {
+			return row
+		}
+		return append(row, spark)
+	}
+
+	if m.showPanel(panelSystem) {
+		// Создаем и настраиваем таблицу
+		t := table.NewWriter()
+		t.SetOutputMirror(m.writer)
+		t.AppendHeader(withHistory(table.Row{"Ресурс", "Использование", "Процент", "Детали"}, "История"))
+
+		// Добавляем данные в таблицу
+		cpuColor := getColorByPercent(stats.CPU, m.thresholds)
+		memColor := getColorByPercent(stats.Memory, m.thresholds)
+		swapColor := getColorByPercent(stats.Swap, m.thresholds)
+		diskColor := getColorByPercent(stats.DiskUsage, m.thresholds)
+
+		t.AppendRow(withHistory(table.Row{
+			"CPU",
+			renderProgressBar(stats.CPU, barWidth),
+			cpuColor(fmt.Sprintf("%.1f%%", stats.CPU)),
+			fmt.Sprintf("%d ядер", len(stats.Cores)),
+		}, m.history.sparkline("cpu", sparkWidth, 100)))
+
+		for i, core := range stats.Cores {
+			coreColor := getColorByPercent(core, m.thresholds)
+			t.AppendRow(withHistory(table.Row{
+				fmt.Sprintf("  CPU%d", i),
+				renderProgressBar(core, barWidth),
+				coreColor(fmt.Sprintf("%.1f%%", core)),
+				"",
+			}, ""))
+		}
+
+		t.AppendRow(withHistory(table.Row{
+			"Load",
+			"",
+			"",
+			fmt.Sprintf("%.2f / %.2f / %.2f", stats.Load1, stats.Load5, stats.Load15),
+		}, ""))
+
+		t.AppendRow(withHistory(table.Row{
+			"Memory",
+			renderProgressBar(stats.Memory, barWidth),
+			memColor(fmt.Sprintf("%.1f%%", stats.Memory)),
+			fmt.Sprintf("%s / %s", formatBytes(stats.UsedMem), formatBytes(stats.TotalMem)),
+		}, m.history.sparkline("memory", sparkWidth, 100)))
+
+		t.AppendRow(withHistory(table.Row{
+			"Swap",
+			renderProgressBar(stats.Swap, barWidth),
+			swapColor(fmt.Sprintf("%.1f%%", stats.Swap)),
+			fmt.Sprintf("%s / %s", formatBytes(stats.UsedSwap), formatBytes(stats.TotalSwap)),
+		}, m.history.sparkline("swap", sparkWidth, 100)))
+
+		t.AppendRow(withHistory(table.Row{
+			"Disk",
+			renderProgressBar(stats.DiskUsage, barWidth),
+			diskColor(fmt.Sprintf("%.1f%%", stats.DiskUsage)),
+			fmt.Sprintf("%s / %s", formatBytes(stats.UsedDisk), formatBytes(stats.TotalDisk)),
+		}, ""))
+
+		for _, mount := range stats.Mounts {
+			mountColor := getColorByPercent(mount.Usage, m.thresholds)
+			t.AppendRow(withHistory(table.Row{
+				"  " + mount.MountPoint,
+				renderProgressBar(mount.Usage, barWidth),
+				mountColor(fmt.Sprintf("%.1f%%", mount.Usage)),
+				fmt.Sprintf("%s / %s, %s, inodes: %.1f%%",
+					formatBytes(mount.Used), formatBytes(mount.Total), mount.FSType, mount.InodeUsage),
+			}, ""))
+		}
+
+		t.AppendRow(withHistory(table.Row{
+			"Events",
+			"",
+			"",
+			fmt.Sprintf("ctx: %s/s, intr: %s/s", formatRate(stats.ContextSwitches), formatRate(stats.Interrupts)),
+		}, ""))
+
+		t.SetStyle(table.StyleLight)
+		t.Render()
+	}
+
+	if m.showPanel(panelIO) && len(stats.DiskIO) > 0 {
+		fmt.Fprintln(m.writer, "\n Disk I/O")
+		io := table.NewWriter()
+		io.SetOutputMirror(m.writer)
+		io.AppendHeader(withHistory(table.Row{"Устройство", "Чтение", "Запись", "IOPS чтения", "IOPS записи"}, "История"))
+		for _, dev := range stats.DiskIO {
+			io.AppendRow(withHistory(table.Row{
+				dev.Device,
+				formatByteRate(dev.ReadBytes),
+				formatByteRate(dev.WriteBytes),
+				fmt.Sprintf("%.1f", dev.ReadOps),
+				fmt.Sprintf("%.1f", dev.WriteOps),
+			}, m.history.sparkline("disk:"+dev.Device, sparkWidth, 0)))
+		}
+		io.SetStyle(table.StyleLight)
+		io.Render()
+	}
+
+	if m.showPanel(panelIO) && len(stats.NetIO) > 0 {
+		fmt.Fprintln(m.writer, "\n Network")
+		net := table.NewWriter()
+		net.SetOutputMirror(m.writer)
+		net.AppendHeader(withHistory(table.Row{"Интерфейс", "Прием", "Передача", "Ошибки приема", "Ошибки передачи"}, "История"))
+		for _, iface := range stats.NetIO {
+			net.AppendRow(withHistory(table.Row{
+				iface.Interface,
+				formatByteRate(iface.RxBytes),
+				formatByteRate(iface.TxBytes),
+				iface.RxErrors,
+				iface.TxErrors,
+			}, m.history.sparkline("net:"+iface.Interface, sparkWidth, 0)))
+		}
+		net.SetStyle(table.StyleLight)
+		net.Render()
+	}
+
+	if m.showPanel(panelProcesses) && len(stats.Processes) > 0 {
+		fmt.Fprintln(m.writer, "\n Processes")
+		m.renderProcesses(stats.Processes)
+	}
+
+	if m.showPanel(panelProcesses) && stats.Target != nil {
+		fmt.Fprintf(m.writer, "\n Process: %s\n", stats.Target.Name)
+		m.renderTarget(stats.Target)
+	}
+
+	if m.showPanel(panelSystem) && stats.Cgroup != nil {
+		fmt.Fprintf(m.writer, "\n Container: cgroup v%d %s\n", stats.Cgroup.Version, stats.Cgroup.Path)
+		cg := table.NewWriter()
+		cg.SetOutputMirror(m.writer)
+		cg.AppendHeader(withHistory(table.Row{"Ресурс", "Использование", "Процент", "Лимит"}, "История"))
+		m.renderCgroup(cg, stats.Cgroup, barWidth, func(row table.Row, key string) table.Row {
+			spark := ""
+			if key != "" {
+				spark = m.history.sparkline(key, sparkWidth, 100)
+			}
+			return withHistory(row, spark)
+		})
+		cg.SetStyle(table.StyleLight)
+		cg.Render()
+	}
+
+	if m.alerts != nil {
+		if active := m.alerts.Active(); len(active) > 0 {
+			fmt.Fprintln(m.writer, "\n Alerts")
+			for _, alert := range active {
+				fmt.Fprintf(m.writer, " %s %s (%.2f)\n", color.New(color.FgRed).Sprint("●"), alert.Rule.Expr, alert.Value)
+			}
+		}
+	}
+
+	if m.ui != nil {
+		if m.panel == panelProcesses && len(stats.Processes) == 0 && stats.Target == nil {
+			fmt.Fprintln(m.writer, "\n Список процессов выключен, запустите монитор с --top N, --pid или --cmd")
+		}
+		fmt.Fprintf(m.writer, "\n %s\n", m.statusLine())
+		return
+	}
+	fmt.Fprintln(m.writer, "\nНажмите Ctrl+C для выхода")
+}
+
+// displaySimple отображает статистику в простом формате
+func (m *Monitor) displaySimple(stats SystemStats) {
+	currentTime := stats.Time.Format("15:04:05")
+
+	cores := make([]string, len(stats.Cores))
+	for i, core := range stats.Cores {
+		cores[i] = fmt.Sprintf("%.1f", core)
+	}
+
+	fmt.Fprintf(m.writer, "%s | CPU: %.1f%% [%s] | Load: %.2f %.2f %.2f | Memory: %.1f%% (%s/%s) | Disk: %.1f%% (%s/%s) | Ctx: %s/s | Intr: %s/s\n",
+		currentTime,
+		stats.CPU, strings.Join(cores, " "),
+		stats.Load1, stats.Load5, stats.Load15,
+		stats.Memory, formatBytes(stats.UsedMem), formatBytes(stats.TotalMem),
+		stats.DiskUsage, formatBytes(stats.UsedDisk), formatBytes(stats.TotalDisk),
+		formatRate(stats.ContextSwitches), formatRate(stats.Interrupts),
+	)
+
+	if target := stats.Target; target != nil {
+		fmt.Fprintf(m.writer, "  %s: %s\n", target.Name, formatTargetSummary(target))
+	}
+
+	if cgroup := stats.Cgroup; cgroup != nil {
+		fmt.Fprintf(m.writer, "  cgroup v%d: CPU: %.1f%% of %s | Memory: %.1f%% (%s/%s) | Throttled: %d (%.1f%%)\n",
+			cgroup.Version,
+			cgroup.CPU, formatCPULimit(cgroup.CPULimit),
+			cgroup.Memory, formatBytes(cgroup.MemoryUsed), formatMemoryLimit(cgroup.MemoryLimit),
+			cgroup.Throttled, cgroup.ThrottledPercent,
+		)
+	}
+
+	for _, process := range stats.Processes {
+		fmt.Fprintf(m.writer, "  %7d %-16s CPU: %5.1f%% RSS: %s (%.1f%%) Threads: %d\n",
+			process.PID, process.Name, process.CPU, formatBytes(process.RSS), process.Memory, process.Threads)
+	}
+}
+
+// renderTarget выводит таблицу отслеживаемого процесса
+func (m *Monitor) renderTarget(target *TargetStats) {
+	if len(target.PIDs) == 0 {
+		fmt.Fprintln(m.writer, " процесс не найден")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(m.writer)
+	t.AppendHeader(table.Row{"Метрика", "Значение"})
+	t.AppendRow(table.Row{"PID", formatPIDs(target.PIDs)})
+	t.AppendRow(table.Row{"CPU", getColorByPercent(target.CPU, m.thresholds)(fmt.Sprintf("%.1f%%", target.CPU))})
+	t.AppendRow(table.Row{"RSS", fmt.Sprintf("%s (%.1f%%)", formatBytes(target.RSS), target.Memory)})
+	t.AppendRow(table.Row{"Потоки", target.Threads})
+	t.AppendRow(table.Row{"Открытые FD", target.FDs})
+	t.AppendRow(table.Row{"Чтение", fmt.Sprintf("%s (всего %s)", formatByteRate(target.ReadRate), formatBytes(target.ReadBytes))})
+	t.AppendRow(table.Row{"Запись", fmt.Sprintf("%s (всего %s)", formatByteRate(target.WriteRate), formatBytes(target.WriteBytes))})
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+// renderCgroup добавляет в таблицу строки использования ресурсов cgroup.
+// row дополняет строку колонкой истории по ключу метрики (пустой ключ - без истории).
+func (m *Monitor) renderCgroup(t table.Writer, cgroup *CgroupStats, barWidth int, row func(table.Row, string) table.Row) {
+	t.AppendRow(row(table.Row{
+		"CPU",
+		renderProgressBar(cgroup.CPU, barWidth),
+		getColorByPercent(cgroup.CPU, m.thresholds)(fmt.Sprintf("%.1f%%", cgroup.CPU)),
+		formatCPULimit(cgroup.CPULimit),
+	}, "cgroup:cpu"))
+	t.AppendRow(row(table.Row{
+		"Memory",
+		renderProgressBar(cgroup.Memory, barWidth),
+		getColorByPercent(cgroup.Memory, m.thresholds)(fmt.Sprintf("%.1f%%", cgroup.Memory)),
+		fmt.Sprintf("%s / %s", formatBytes(cgroup.MemoryUsed), formatMemoryLimit(cgroup.MemoryLimit)),
+	}, "cgroup:memory"))
+	t.AppendRow(row(table.Row{
+		"Throttling",
+		"",
+		getColorByPercent(cgroup.ThrottledPercent, m.thresholds)(fmt.Sprintf("%.1f%%", cgroup.ThrottledPercent)),
+		fmt.Sprintf("%d из %d периодов, %.1f с", cgroup.Throttled, cgroup.Periods, cgroup.ThrottledTime),
+	}, ""))
+}
+
+// formatCPULimit форматирует лимит CPU cgroup в ядрах
+func formatCPULimit(cores float64) string {
+	if cores == 0 {
+		return "без лимита"
+	}
+	return fmt.Sprintf("%.2f CPU", cores)
+}
+
+// formatMemoryLimit форматирует лимит памяти cgroup
+func formatMemoryLimit(limit uint64) string {
+	if limit == 0 {
+		return "без лимита"
+	}
+	return formatBytes(limit)
+}
+
+// formatTargetSummary возвращает однострочное описание отслеживаемого процесса
+func formatTargetSummary(target *TargetStats) string {
+	if len(target.PIDs) == 0 {
+		return "процесс не найден"
+	}
+	return fmt.Sprintf("PID %s | CPU: %.1f%% | RSS: %s | Threads: %d | FDs: %d | IO: R %s W %s",
+		formatPIDs(target.PIDs), target.CPU, formatBytes(target.RSS), target.Threads, target.FDs,
+		formatByteRate(target.ReadRate), formatByteRate(target.WriteRate))
+}
+
+// formatPIDs форматирует список PID через пробел
+func formatPIDs(pids []int) string {
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = strconv.Itoa(pid)
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderProcesses выводит таблицу процессов
+func (m *Monitor) renderProcesses(processes []ProcessStats) {
+	t := table.NewWriter()
+	t.SetOutputMirror(m.writer)
+	t.AppendHeader(table.Row{"PID", "Имя", "CPU", "RSS", "Память", "Потоки", "Состояние"})
+	for _, process := range processes {
+		t.AppendRow(table.Row{
+			process.PID,
+			process.Name,
+			getColorByPercent(process.CPU, m.thresholds)(fmt.Sprintf("%.1f%%", process.CPU)),
+			formatBytes(process.RSS),
+			fmt.Sprintf("%.1f%%", process.Memory),
+			process.Threads,
+			process.State,
+		})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+// displayCSV отображает статистику в формате CSV.
+// Заголовок выводится перед первой строкой, так как набор колонок
+// зависит от собранных данных (ядер, точек монтирования, устройств и интерфейсов).
+func (m *Monitor) displayCSV(stats SystemStats) {
+	w := csv.NewWriter(m.writer)
+
+	if m.csvLayout == nil {
+		m.csvLayout = newCSVLayout(stats)
+		m.csvLayout.rawBytes = m.rawBytes
+		w.Write(m.csvLayout.header())
+	}
+
+	currentTime := stats.Time.Format("2006-01-02 15:04:05")
+	w.Write(m.csvLayout.record(stats, currentTime))
+	w.Flush()
+}
+
+// displayJSON отображает статистику отдельным JSON-объектом с отступами.
+// Байты и проценты выводятся числами, время - в формате RFC 3339.
+func (m *Monitor) displayJSON(stats SystemStats) error {
+	encoder := json.NewEncoder(m.writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(stats); err != nil {
+		return fmt.Errorf("не удалось сформировать JSON: %w", err)
+	}
+	return nil
+}
+
+// csvLayout фиксирует набор динамических колонок CSV на момент вывода заголовка,
+// чтобы строки оставались согласованными с заголовком при появлении или
+// исчезновении ядер, точек монтирования, устройств и интерфейсов.
+type csvLayout struct {
+	cores      int
+	mounts     []string
+	devices    []string
+	interfaces []string
+	target     bool
+	cgroup     bool
+	rawBytes   bool // Байты и скорости числами без единиц измерения
+}
+
+// newCSVLayout создает набор колонок по первой статистике
+func newCSVLayout(stats SystemStats) *csvLayout {
+	layout := &csvLayout{cores: len(stats.Cores), target: stats.Target != nil, cgroup: stats.Cgroup != nil}
+	for _, mount := range stats.Mounts {
+		layout.mounts = append(layout.mounts, mount.MountPoint)
+	}
+	for _, io := range stats.DiskIO {
+		layout.devices = append(layout.devices, io.Device)
+	}
+	for _, io := range stats.NetIO {
+		layout.interfaces = append(layout.interfaces, io.Interface)
+	}
+	return layout
+}
+
+// header возвращает заголовок CSV.
+// Новые колонки добавляются в конец, чтобы не сдвигать существующие.
+func (l *csvLayout) header() []string {
+	header := []string{
+		"Time", "CPU (%)",
+		"Memory (%)", "Memory Used", "Memory Total",
+		"Swap (%)", "Swap Used", "Swap Total",
+		"Disk (%)", "Disk Used", "Disk Total",
+		"Load 1", "Load 5", "Load 15",
+		"Context Switches/s", "Interrupts/s",
+	}
+	for i := 0; i < l.cores; i++ {
+		header = append(header, fmt.Sprintf("CPU%d (%%)", i))
+	}
+	for _, mountPoint := range l.mounts {
+		header = append(header,
+			fmt.Sprintf("Disk %s (%%)", mountPoint),
+			fmt.Sprintf("Disk %s Used", mountPoint),
+			fmt.Sprintf("Disk %s Total", mountPoint),
+			fmt.Sprintf("Inodes %s (%%)", mountPoint),
+		)
+	}
+	for _, device := range l.devices {
+		header = append(header,
+			fmt.Sprintf("IO %s Read/s", device),
+			fmt.Sprintf("IO %s Write/s", device),
+			fmt.Sprintf("IO %s Read IOPS", device),
+			fmt.Sprintf("IO %s Write IOPS", device),
+		)
+	}
+	for _, iface := range l.interfaces {
+		header = append(header,
+			fmt.Sprintf("Net %s RX/s", iface),
+			fmt.Sprintf("Net %s TX/s", iface),
+			fmt.Sprintf("Net %s RX Errors", iface),
+			fmt.Sprintf("Net %s TX Errors", iface),
+		)
+	}
+	if l.target {
+		header = append(header,
+			"Target PIDs", "Target CPU (%)", "Target RSS", "Target Threads", "Target FDs",
+			"Target Read/s", "Target Write/s", "Target Read Total", "Target Write Total",
+		)
+	}
+	if l.cgroup {
+		header = append(header,
+			"Cgroup CPU (%)", "Cgroup CPU Limit", "Cgroup Memory (%)", "Cgroup Memory Used", "Cgroup Memory Limit",
+			"Cgroup Throttled", "Cgroup Throttled (%)",
+		)
+	}
+	return header
+}
+
+// record возвращает строку CSV для статистики.
+// Колонки, для которых в статистике нет данных, остаются пустыми.
+func (l *csvLayout) record(stats SystemStats, currentTime string) []string {
+	record := []string{
+		currentTime, fmt.Sprintf("%.1f", stats.CPU),
+		fmt.Sprintf("%.1f", stats.Memory), l.bytes(stats.UsedMem), l.bytes(stats.TotalMem),
+		fmt.Sprintf("%.1f", stats.Swap), l.bytes(stats.UsedSwap), l.bytes(stats.TotalSwap),
+		fmt.Sprintf("%.1f", stats.DiskUsage), l.bytes(stats.UsedDisk), l.bytes(stats.TotalDisk),
+		fmt.Sprintf("%.2f", stats.Load1), fmt.Sprintf("%.2f", stats.Load5), fmt.Sprintf("%.2f", stats.Load15),
+		fmt.Sprintf("%.1f", stats.ContextSwitches), fmt.Sprintf("%.1f", stats.Interrupts),
+	}
+	for i := 0; i < l.cores; i++ {
+		if i < len(stats.Cores) {
+			record = append(record, fmt.Sprintf("%.1f", stats.Cores[i]))
+		} else {
+			record = append(record, "")
+		}
+	}
+	for _, mountPoint := range l.mounts {
+		mount, ok := findMount(stats.Mounts, mountPoint)
+		if !ok {
+			record = append(record, "", "", "", "")
+			continue
+		}
+		record = append(record,
+			fmt.Sprintf("%.1f", mount.Usage), l.bytes(mount.Used), l.bytes(mount.Total),
+			fmt.Sprintf("%.1f", mount.InodeUsage),
+		)
+	}
+	for _, device := range l.devices {
+		io, ok := findDiskIO(stats.DiskIO, device)
+		if !ok {
+			record = append(record, "", "", "", "")
+			continue
+		}
+		record = append(record,
+			l.byteRate(io.ReadBytes), l.byteRate(io.WriteBytes),
+			fmt.Sprintf("%.1f", io.ReadOps), fmt.Sprintf("%.1f", io.WriteOps),
+		)
+	}
+	for _, iface := range l.interfaces {
+		io, ok := findNetIO(stats.NetIO, iface)
+		if !ok {
+			record = append(record, "", "", "", "")
+			continue
+		}
+		record = append(record,
+			l.byteRate(io.RxBytes), l.byteRate(io.TxBytes),
+			fmt.Sprintf("%d", io.RxErrors), fmt.Sprintf("%d", io.TxErrors),
+		)
+	}
+	if l.target {
+		if target := stats.Target; target != nil && len(target.PIDs) > 0 {
+			record = append(record,
+				formatPIDs(target.PIDs), fmt.Sprintf("%.1f", target.CPU), l.bytes(target.RSS),
+				fmt.Sprintf("%d", target.Threads), fmt.Sprintf("%d", target.FDs),
+				l.byteRate(target.ReadRate), l.byteRate(target.WriteRate),
+				l.bytes(target.ReadBytes), l.bytes(target.WriteBytes),
+			)
+		} else {
+			record = append(record, "", "", "", "", "", "", "", "", "")
+		}
+	}
+	if l.cgroup {
+		if cgroup := stats.Cgroup; cgroup != nil {
+			cpuLimit, memoryLimit := "", ""
+			if cgroup.CPULimit > 0 {
+				cpuLimit = fmt.Sprintf("%.2f", cgroup.CPULimit)
+			}
+			if cgroup.MemoryLimit > 0 {
+				memoryLimit = l.bytes(cgroup.MemoryLimit)
+			}
+			record = append(record,
+				fmt.Sprintf("%.1f", cgroup.CPU), cpuLimit,
+				fmt.Sprintf("%.1f", cgroup.Memory), l.bytes(cgroup.MemoryUsed), memoryLimit,
+				fmt.Sprintf("%d", cgroup.Throttled), fmt.Sprintf("%.1f", cgroup.ThrottledPercent),
+			)
+		} else {
+			record = append(record, "", "", "", "", "", "", "")
+		}
+	}
+	return record
+}
+
+// bytes форматирует количество байт для CSV
+func (l *csvLayout) bytes(value uint64) string {
+	if l.rawBytes {
+		return strconv.FormatUint(value, 10)
+	}
+	return formatBytes(value)
+}
+
+// byteRate форматирует скорость в байтах в секунду для CSV
+func (l *csvLayout) byteRate(value float64) string {
+	if l.rawBytes {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return formatByteRate(value)
+}
+
+// findMount ищет статистику точки монтирования по пути
+func findMount(mounts []MountStats, mountPoint string) (MountStats, bool) {
+	for _, mount := range mounts {
+		if mount.MountPoint == mountPoint {
+			return mount, true
+		}
+	}
+	return MountStats{}, false
+}
+
+// findDiskIO ищет статистику ввода-вывода устройства по имени
+func findDiskIO(stats []DiskIOStats, device string) (DiskIOStats, bool) {
+	for _, io := range stats {
+		if io.Device == device {
+			return io, true
+		}
+	}
+	return DiskIOStats{}, false
+}
+
+// findNetIO ищет статистику сетевого интерфейса по имени
+func findNetIO(stats []NetIOStats, iface string) (NetIOStats, bool) {
+	for _, io := range stats {
+		if io.Interface == iface {
+			return io, true
+		}
+	}
+	return NetIOStats{}, false
+}
+
+// renderProgressBar создает строку прогресс-бара
+func renderProgressBar(percent float64, width int) string {
+	if width < 10 {
+		width = 10
+	}
+
+	completed := int(percent / 100 * float64(width))
+	if completed > width {
+		completed = width
+	}
+
+	bar := "["
+	for i := 0; i < width; i++ {
+		if i < completed {
+			bar += "="
+		} else {
+			bar += " "
+		}
+	}
+	bar += "]"
+	return bar
+}
+
+// formatBytes форматирует байты в читаемый формат
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// formatByteRate форматирует скорость передачи данных в читаемый формат
+func formatByteRate(rate float64) string {
+	if rate < 0 {
+		rate = 0
+	}
+	return formatBytes(uint64(rate)) + "/s"
+}
+
+// formatRate форматирует частоту событий в читаемый формат
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 1e6:
+		return fmt.Sprintf("%.1fM", rate/1e6)
+	case rate >= 1e3:
+		return fmt.Sprintf("%.1fk", rate/1e3)
+	}
+	return fmt.Sprintf("%.0f", rate)
+}
+
+// getColorByPercent возвращает функцию цвета в зависимости от процента и порогов
+func getColorByPercent(percent float64, thresholds Thresholds) func(a ...interface{}) string {
+	if percent >= thresholds.Critical {
+		return color.New(color.FgRed).SprintFunc()
+	} else if percent >= thresholds.Warning {
+		return color.New(color.FgYellow).SprintFunc()
+	}
+	return color.New(color.FgGreen).SprintFunc()
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import "strings"
+
+// DefaultHistorySize количество замеров в истории панели по умолчанию
+const DefaultHistorySize = 60
+
+// sparkTicks символы спарклайна от минимального к максимальному значению
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// series хранит последние значения метрики
+type series struct {
+	values []float64
+	size   int
+}
+
+// push добавляет значение, отбрасывая самые старые сверх размера окна
+func (s *series) push(value float64) {
+	s.values = append(s.values, value)
+	if len(s.values) > s.size {
+		s.values = append(s.values[:0], s.values[len(s.values)-s.size:]...)
+	}
+}
+
+// history хранит скользящее окно замеров для спарклайнов панели.
+// Ключи: cpu, memory, swap, disk:<устройство> (чтение + запись), net:<интерфейс> (прием + передача),
+// cgroup:cpu и cgroup:memory.
+type history struct {
+	size   int
+	series map[string]*series
+}
+
+// newHistory создает историю на size замеров
+func newHistory(size int) *history {
+	return &history{size: size, series: make(map[string]*series)}
+}
+
+// add добавляет замер в историю.
+// История исчезнувших устройств и интерфейсов удаляется.
+func (h *history) add(stats SystemStats) {
+	current := make(map[string]*series, len(h.series))
+	push := func(key string, value float64) {
+		s, ok := h.series[key]
+		if !ok {
+			s = &series{size: h.size}
+		}
+		s.push(value)
+		current[key] = s
+	}
+
+	push("cpu", stats.CPU)
+	push("memory", stats.Memory)
+	push("swap", stats.Swap)
+	for _, io := range stats.DiskIO {
+		push("disk:"+io.Device, io.ReadBytes+io.WriteBytes)
+	}
+	for _, io := range stats.NetIO {
+		push("net:"+io.Interface, io.RxBytes+io.TxBytes)
+	}
+	if stats.Cgroup != nil {
+		push("cgroup:cpu", stats.Cgroup.CPU)
+		push("cgroup:memory", stats.Cgroup.Memory)
+	}
+
+	h.series = current
+}
+
+// sparkline строит спарклайн метрики по ключу; пустая строка, если история выключена
+func (h *history) sparkline(key string, width int, max float64) string {
+	if h == nil {
+		return ""
+	}
+	var values []float64
+	if s, ok := h.series[key]; ok {
+		values = s.values
+	}
+	return sparkline(values, width, max)
+}
+
+// sparkline строит спарклайн из последних width значений.
+// Значения масштабируются к max; если max не больше нуля, - к максимуму окна.
+// Строка дополняется пробелами слева до ширины width.
+func sparkline(values []float64, width int, max float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	if max <= 0 {
+		for _, value := range values {
+			if value > max {
+				max = value
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, value := range values {
+		level := 0
+		if max > 0 && value > 0 {
+			level = int(value / max * float64(len(sparkTicks)-1))
+			if level >= len(sparkTicks) {
+				level = len(sparkTicks) - 1
+			}
+		}
+		b.WriteRune(sparkTicks[level])
+	}
+	return b.String()
+}
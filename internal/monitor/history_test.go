@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries_Push(t *testing.T) {
+	s := series{size: 3}
+	for i := 1; i <= 5; i++ {
+		s.push(float64(i))
+	}
+	assert.Equal(t, []float64{3, 4, 5}, s.values)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", sparkline([]float64{0, 50, 100}, 3, 100))
+	// Дополнение пробелами слева
+	assert.Equal(t, "  ▁█", sparkline([]float64{0, 100}, 4, 100))
+	// Только последние width значений
+	assert.Equal(t, "█▁", sparkline([]float64{0, 100, 0}, 2, 100))
+	// Масштаб по максимуму окна
+	assert.Equal(t, "▁▄█", sparkline([]float64{0, 1024, 2048}, 3, 0))
+	assert.Equal(t, "▁▁", sparkline([]float64{0, 0}, 2, 0))
+	// Значения выше максимума
+	assert.Equal(t, "█", sparkline([]float64{150}, 1, 100))
+	assert.Equal(t, "", sparkline([]float64{1}, 0, 100))
+}
+
+func TestHistory_Add(t *testing.T) {
+	h := newHistory(2)
+	h.add(SystemStats{CPU: 10, DiskIO: []DiskIOStats{{Device: "sda", ReadBytes: 1, WriteBytes: 2}}})
+	h.add(SystemStats{CPU: 20, DiskIO: []DiskIOStats{{Device: "sda", ReadBytes: 3, WriteBytes: 4}}})
+	h.add(SystemStats{CPU: 30, DiskIO: []DiskIOStats{{Device: "sda", ReadBytes: 5, WriteBytes: 6}}, NetIO: []NetIOStats{{Interface: "eth0", RxBytes: 1}}})
+
+	assert.Equal(t, []float64{20, 30}, h.series["cpu"].values)
+	assert.Equal(t, []float64{7, 11}, h.series["disk:sda"].values)
+	assert.Equal(t, []float64{1}, h.series["net:eth0"].values)
+
+	// Исчезнувшее устройство удаляется
+	h.add(SystemStats{CPU: 40})
+	assert.NotContains(t, h.series, "disk:sda")
+	assert.Equal(t, "   ", h.sparkline("disk:sda", 3, 0))
+
+	var disabled *history
+	assert.Equal(t, "", disabled.sparkline("cpu", 10, 100))
+}
+
+func TestDisplayDashboard_History(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := &Monitor{
+		interval:    time.Second,
+		displayMode: "dashboard",
+		ctx:         context.Background(),
+		cancelFunc:  func() {},
+		writer:      &buf,
+		thresholds:  DefaultThresholds,
+		history:     newHistory(DefaultHistorySize),
+	}
+
+	for _, cpu := range []float64{0, 50, 100} {
+		buf.Reset()
+		assert.NoError(t, monitor.handleStats(SystemStats{CPU: cpu, DiskIO: []DiskIOStats{{Device: "sda", ReadBytes: cpu}}}))
+	}
+
+	output := buf.String()
+	assert.Contains(t, output, "ИСТОРИЯ")
+	assert.Contains(t, output, "▁▄█")
+	assert.Equal(t, 2, strings.Count(output, "▁▄█"))
+}
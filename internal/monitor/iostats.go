@@ -0,0 +1,204 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sectorSize размер сектора в /proc/diskstats (всегда 512 байт, независимо от устройства)
+const sectorSize = 512
+
+// DiskIOStats представляет пропускную способность блочного устройства
+type DiskIOStats struct {
+	Device     string  `json:"device"`      // Имя устройства
+	ReadBytes  float64 `json:"read_bytes"`  // Прочитано байт в секунду
+	WriteBytes float64 `json:"write_bytes"` // Записано байт в секунду
+	ReadOps    float64 `json:"read_ops"`    // Операций чтения в секунду
+	WriteOps   float64 `json:"write_ops"`   // Операций записи в секунду
+}
+
+// NetIOStats представляет пропускную способность сетевого интерфейса
+type NetIOStats struct {
+	Interface string  `json:"interface"` // Имя интерфейса
+	RxBytes   float64 `json:"rx_bytes"`  // Принято байт в секунду
+	TxBytes   float64 `json:"tx_bytes"`  // Отправлено байт в секунду
+	RxErrors  uint64  `json:"rx_errors"` // Ошибок приема с момента загрузки
+	TxErrors  uint64  `json:"tx_errors"` // Ошибок передачи с момента загрузки
+}
+
+// diskCounters содержит счетчики устройства из /proc/diskstats
+type diskCounters struct {
+	reads        uint64
+	sectorsRead  uint64
+	writes       uint64
+	sectorsWrite uint64
+}
+
+// netCounters содержит счетчики интерфейса из /proc/net/dev
+type netCounters struct {
+	rxBytes  uint64
+	rxErrors uint64
+	txBytes  uint64
+	txErrors uint64
+}
+
+// collectDiskIO вычисляет скорость ввода-вывода устройств с предыдущего замера.
+// Устройства без единой операции (неиспользуемые loop, ram) пропускаются.
+func (c *Collector) collectDiskIO(elapsed float64) ([]DiskIOStats, error) {
+	devices, counters, err := readDiskStats(c.procPath("diskstats"))
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []DiskIOStats
+	for _, device := range devices {
+		cur := counters[device]
+		if cur == (diskCounters{}) {
+			continue
+		}
+
+		io := DiskIOStats{Device: device}
+		if prev, ok := c.prevDisk[device]; ok && elapsed > 0 {
+			io.ReadBytes = counterRate(prev.sectorsRead, cur.sectorsRead, elapsed) * sectorSize
+			io.WriteBytes = counterRate(prev.sectorsWrite, cur.sectorsWrite, elapsed) * sectorSize
+			io.ReadOps = counterRate(prev.reads, cur.reads, elapsed)
+			io.WriteOps = counterRate(prev.writes, cur.writes, elapsed)
+		}
+		stats = append(stats, io)
+	}
+	c.prevDisk = counters
+
+	return stats, nil
+}
+
+// collectNetIO вычисляет скорость передачи данных по интерфейсам с предыдущего замера
+func (c *Collector) collectNetIO(elapsed float64) ([]NetIOStats, error) {
+	interfaces, counters, err := readNetDev(c.procPath("net/dev"))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]NetIOStats, 0, len(interfaces))
+	for _, iface := range interfaces {
+		cur := counters[iface]
+		io := NetIOStats{
+			Interface: iface,
+			RxErrors:  cur.rxErrors,
+			TxErrors:  cur.txErrors,
+		}
+		if prev, ok := c.prevNet[iface]; ok && elapsed > 0 {
+			io.RxBytes = counterRate(prev.rxBytes, cur.rxBytes, elapsed)
+			io.TxBytes = counterRate(prev.txBytes, cur.txBytes, elapsed)
+		}
+		stats = append(stats, io)
+	}
+	c.prevNet = counters
+
+	return stats, nil
+}
+
+// readDiskStats читает счетчики блочных устройств из /proc/diskstats.
+// Возвращает имена устройств в порядке следования в файле и счетчики по имени.
+func readDiskStats(path string) ([]string, map[string]diskCounters, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var devices []string
+	counters := make(map[string]diskCounters)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// major minor name reads merged sectors ms writes merged sectors ms ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		values, err := parseCounters(fields[3:10])
+		if err != nil {
+			return nil, nil, fmt.Errorf("неверный формат %s: %w", path, err)
+		}
+
+		device := fields[2]
+		if _, ok := counters[device]; !ok {
+			devices = append(devices, device)
+		}
+		counters[device] = diskCounters{
+			reads:        values[0],
+			sectorsRead:  values[2],
+			writes:       values[4],
+			sectorsWrite: values[6],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+
+	return devices, counters, nil
+}
+
+// readNetDev читает счетчики сетевых интерфейсов из /proc/net/dev.
+// Возвращает имена интерфейсов в порядке следования в файле и счетчики по имени.
+func readNetDev(path string) ([]string, map[string]netCounters, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var interfaces []string
+	counters := make(map[string]netCounters)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Строки заголовка не содержат двоеточия после имени интерфейса
+		name, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		// rx: bytes packets errs drop fifo frame compressed multicast
+		// tx: bytes packets errs drop fifo colls carrier compressed
+		fields := strings.Fields(rest)
+		if len(fields) < 11 {
+			continue
+		}
+
+		values, err := parseCounters(fields[:11])
+		if err != nil {
+			return nil, nil, fmt.Errorf("неверный формат %s: %w", path, err)
+		}
+
+		iface := strings.TrimSpace(name)
+		if _, ok := counters[iface]; !ok {
+			interfaces = append(interfaces, iface)
+		}
+		counters[iface] = netCounters{
+			rxBytes:  values[0],
+			rxErrors: values[2],
+			txBytes:  values[8],
+			txErrors: values[10],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+
+	return interfaces, counters, nil
+}
+
+// parseCounters разбирает список беззнаковых счетчиков
+func parseCounters(fields []string) ([]uint64, error) {
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("неверное значение счетчика %q", field)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetDev возвращает содержимое /proc/net/dev с интерфейсом eth0
+func testNetDev(counters string) string {
+	return "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"  eth0: " + counters + "\n"
+}
+
+func TestReadDiskStats(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "diskstats", `   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0
+   8       0 sda 1200 30 48000 900 600 20 24000 400 0 1000 1300 0 0 0 0 0 0
+   8       1 sda1 1100 30 47000 800 600 20 24000 400 0 900 1200
+short line
+`)
+
+	devices, counters, err := readDiskStats(filepath.Join(root, "diskstats"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"loop0", "sda", "sda1"}, devices)
+	assert.Equal(t, diskCounters{reads: 1200, sectorsRead: 48000, writes: 600, sectorsWrite: 24000}, counters["sda"])
+	assert.Equal(t, diskCounters{}, counters["loop0"])
+
+	writeProcFile(t, root, "diskstats", "   8       0 sda x 0 0 0 0 0 0 0\n")
+	_, _, err = readDiskStats(filepath.Join(root, "diskstats"))
+	assert.Error(t, err)
+
+	_, _, err = readDiskStats(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestReadNetDev(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "net/dev", testNetDev("850388 103 4 0 0 0 0 0 15125 153 2 0 0 0 0 0")+
+		"    lo:14212067 1967 0 0 0 0 0 0 14212067 1967 0 0 0 0 0 0\n")
+
+	interfaces, counters, err := readNetDev(filepath.Join(root, "net/dev"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eth0", "lo"}, interfaces)
+	assert.Equal(t, netCounters{rxBytes: 850388, rxErrors: 4, txBytes: 15125, txErrors: 2}, counters["eth0"])
+	// Имя интерфейса может быть записано вплотную к счетчикам
+	assert.Equal(t, uint64(14212067), counters["lo"].rxBytes)
+
+	writeProcFile(t, root, "net/dev", testNetDev("1 2 3 4 5 6 7 8 x 0 0 0 0 0 0 0"))
+	_, _, err = readNetDev(filepath.Join(root, "net/dev"))
+	assert.Error(t, err)
+}
+
+func TestCollector_CollectDiskIO(t *testing.T) {
+	root := t.TempDir()
+	collector := NewCollector(Options{ProcFS: root})
+
+	writeProcFile(t, root, "diskstats", "   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0\n"+
+		"   8       0 sda 10 0 100 0 10 0 100 0 0 0 0\n")
+	stats, err := collector.collectDiskIO(0)
+	require.NoError(t, err)
+	// Неиспользуемые устройства пропускаются, первый замер без скорости
+	assert.Equal(t, []DiskIOStats{{Device: "sda"}}, stats)
+
+	writeProcFile(t, root, "diskstats", "   8       0 sda 20 0 300 0 10 0 100 0 0 0 0\n"+
+		"   8      16 sdb 5 0 10 0 0 0 0 0 0 0 0\n")
+	stats, err = collector.collectDiskIO(1)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, DiskIOStats{Device: "sda", ReadBytes: 200 * sectorSize, ReadOps: 10}, stats[0])
+	// Новое устройство появляется без скорости
+	assert.Equal(t, DiskIOStats{Device: "sdb"}, stats[1])
+}
@@ -0,0 +1,222 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeFormat формат отметки времени в имени архивного файла журнала
+const backupTimeFormat = "20060102T150405"
+
+// LogOptions определяет запись замеров в журнал с ротацией
+type LogOptions struct {
+	Path        string        // Файл журнала, пустая строка - журнал не ведется
+	MaxSize     int64         // Размер файла (байты), после которого начинается новый файл, 0 - без ограничения
+	RotateEvery time.Duration // Период начала нового файла, 0 - без ротации по времени
+	MaxBackups  int           // Сколько архивных файлов хранить, 0 - без ограничения
+	MaxAge      time.Duration // Сколько хранить архивные файлы, 0 - без ограничения
+}
+
+// DefaultLogPath возвращает путь к журналу, если запись включена в конфигурации без указания файла.
+// Журнал хранится в пользовательском каталоге кэша, а не в общем временном каталоге, где
+// другой пользователь может заранее создать файл или ссылку с тем же именем. Если каталог
+// кэша не определен, журнал пишется в текущий каталог.
+func DefaultLogPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "devhelper-monitor.log"
+	}
+	return filepath.Join(cacheDir, "devhelper", "monitor.log")
+}
+
+// rotatingFile дописывает данные в файл журнала, архивируя его по размеру и времени.
+// Архивные файлы называются <имя>-<время><расширение>, например monitor-20250101T120000.log.
+type rotatingFile struct {
+	opts   LogOptions
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+	rename func(oldpath, newpath string) error
+}
+
+// openRotatingFile открывает файл журнала для дописывания, создавая каталог при необходимости
+func openRotatingFile(opts LogOptions) (*rotatingFile, error) {
+	f := &rotatingFile{opts: opts, now: time.Now, rename: os.Rename}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог журнала: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open открывает текущий файл журнала
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть журнал: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("не удалось открыть журнал: %w", err)
+	}
+	f.file, f.size, f.opened = file, info.Size(), f.now()
+	return nil
+}
+
+// Write дописывает данные, предварительно начиная новый файл, если текущий
+// превысит MaxSize или открыт дольше RotateEvery
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	sizeExceeded := f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize
+	expired := f.opts.RotateEvery > 0 && f.now().Sub(f.opened) >= f.opts.RotateEvery
+	if sizeExceeded || expired {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close закрывает файл журнала
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// rotate переименовывает текущий файл в архивный, открывает новый и удаляет устаревшие архивы.
+// Файл закрывается до переименования, иначе в Windows его нельзя переименовать. Если
+// переименовать не удалось, текущий файл открывается снова, чтобы следующие записи не терялись.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("не удалось закрыть журнал: %w", err)
+	}
+	if err := f.rename(f.opts.Path, f.backupName(f.now())); err != nil {
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("не удалось переименовать журнал: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.prune()
+}
+
+// backupName возвращает имя архивного файла для момента ротации (время в UTC)
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.opts.Path)
+	base := strings.TrimSuffix(f.opts.Path, ext)
+	stamp := t.UTC().Format(backupTimeFormat)
+	name := base + "-" + stamp + ext
+	// Несколько ротаций в одну секунду не должны перезаписывать друг друга
+	for i := 1; fileExists(name); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+// logBackup описывает архивный файл журнала
+type logBackup struct {
+	path    string
+	rotated time.Time // Время ротации из имени файла
+}
+
+// backups возвращает архивные файлы журнала от новых к старым
+func (f *rotatingFile) backups() ([]logBackup, error) {
+	dir := filepath.Dir(f.opts.Path)
+	ext := filepath.Ext(f.opts.Path)
+	prefix := filepath.Base(strings.TrimSuffix(f.opts.Path, ext)) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать каталог журнала: %w", err)
+	}
+
+	var backups []logBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+		backups = append(backups, logBackup{path: filepath.Join(dir, name), rotated: rotated})
+	}
+
+	// При совпадении времени более новый архив имеет порядковый номер и более длинное имя
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].rotated.Equal(backups[j].rotated) {
+			return backups[i].rotated.After(backups[j].rotated)
+		}
+		if len(backups[i].path) != len(backups[j].path) {
+			return len(backups[i].path) > len(backups[j].path)
+		}
+		return backups[i].path > backups[j].path
+	})
+	return backups, nil
+}
+
+// prune удаляет архивы сверх MaxBackups и старше MaxAge
+func (f *rotatingFile) prune() error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+
+	for i, backup := range backups {
+		tooMany := f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups
+		tooOld := f.opts.MaxAge > 0 && f.now().Sub(backup.rotated) > f.opts.MaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(backup.path); err != nil {
+				return fmt.Errorf("не удалось удалить старый журнал: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// fileExists проверяет, существует ли файл
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// openLog открывает журнал замеров, если он задан; возвращает функцию закрытия
+func (m *Monitor) openLog() (func(), error) {
+	if m.log.Path == "" {
+		return func() {}, nil
+	}
+	file, err := openRotatingFile(m.log)
+	if err != nil {
+		return nil, err
+	}
+	m.logFile = file
+	return func() {
+		file.Close()
+		m.logFile = nil
+	}, nil
+}
+
+// writeLog записывает замер в журнал одной строкой JSON
+func (m *Monitor) writeLog(stats SystemStats) error {
+	if err := json.NewEncoder(m.logFile).Encode(stats); err != nil {
+		return fmt.Errorf("не удалось записать замер в журнал: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listLogDir возвращает отсортированные имена файлов каталога журнала
+func listLogDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func TestRotatingFile_RotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "monitor.log")
+
+	file, err := openRotatingFile(LogOptions{Path: path, MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	file.now = func() time.Time { return now }
+
+	// Строка, которая не помещается в текущий файл, начинает новый
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+	require.NoError(t, file.Close())
+
+	// Хранятся только два последних архива
+	assert.Equal(t, []string{"monitor-20250101T120002.log", "monitor-20250101T120003.log", "monitor.log"},
+		listLogDir(t, filepath.Join(dir, "logs")))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "logs", "monitor-20250101T120003.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
+
+func TestRotatingFile_RotateByTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "monitor.log")
+
+	// Существующий журнал дописывается
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+	// Архив старше MaxAge удаляется при ротации, посторонние файлы не трогаются
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "monitor-20241201T000000.log"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "monitor-notes.log"), nil, 0644))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	file := &rotatingFile{opts: LogOptions{Path: path, RotateEvery: time.Hour, MaxAge: 7 * 24 * time.Hour}, now: func() time.Time { return now }, rename: os.Rename}
+	require.NoError(t, file.open())
+
+	_, err := file.Write([]byte("a\n"))
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = file.Write([]byte("b\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, []string{"monitor-20250101T130000.log", "monitor-notes.log", "monitor.log"}, listLogDir(t, dir))
+	data, err := os.ReadFile(filepath.Join(dir, "monitor-20250101T130000.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "old\na\n", string(data))
+}
+
+func TestRotatingFile_RenameFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor.log")
+	file, err := openRotatingFile(LogOptions{Path: path, MaxSize: 10})
+	require.NoError(t, err)
+	file.rename = func(string, string) error { return os.ErrPermission }
+
+	_, err = file.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("second\n"))
+	assert.ErrorIs(t, err, os.ErrPermission)
+
+	// Текущий файл открыт снова, и следующая ротация выполняется
+	file.rename = os.Rename
+	_, err = file.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(data))
+	assert.Len(t, listLogDir(t, filepath.Dir(path)), 2)
+}
+
+func TestRotatingFile_BackupNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	file := &rotatingFile{opts: LogOptions{Path: filepath.Join(dir, "monitor.log")}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first := file.backupName(now)
+	require.NoError(t, os.WriteFile(first, nil, 0644))
+	second := file.backupName(now)
+	assert.Equal(t, filepath.Join(dir, "monitor-20250101T120000.1.log"), second)
+}
+
+func TestMonitor_StartWithLog(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	path := filepath.Join(t.TempDir(), "monitor.log")
+	monitor := NewMonitor(10*time.Millisecond, "simple", Options{Count: 2, Log: LogOptions{Path: path}})
+	var buf bytes.Buffer
+	monitor.writer = &buf
+
+	// Журнал ведется параллельно с выводом на экран
+	require.NoError(t, monitor.Start())
+	assert.Equal(t, 2, strings.Count(buf.String(), "CPU:"))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	samples, err := ReadSession(file)
+	require.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Nil(t, monitor.logFile)
+}
+
+func TestDefaultLogPath(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Каталог кэша задается через XDG_CACHE_HOME только на Linux")
+	}
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	assert.Equal(t, filepath.Join(cacheDir, "devhelper", "monitor.log"), DefaultLogPath())
+
+	// Без каталога кэша журнал не пишется в общий временный каталог
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+	assert.Equal(t, "devhelper-monitor.log", DefaultLogPath())
+}
@@ -2,31 +2,70 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
-	"runtime"
+	"regexp"
 	"syscall"
 	"time"
 
-	"github.com/fatih/color"
-	"github.com/jedib0t/go-pretty/v6/table"
+	"devhelper/pkg/config"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 // SystemStats представляет статистику системных ресурсов
 type SystemStats struct {
-	CPU       float64 // Использование CPU (%)
-	Memory    float64 // Использование памяти (%)
-	UsedMem   uint64  // Использовано памяти (байты)
-	TotalMem  uint64  // Всего памяти (байты)
-	Swap      float64 // Использование swap (%)
-	UsedSwap  uint64  // Использовано swap (байты)
-	TotalSwap uint64  // Всего swap (байты)
-	DiskUsage float64 // Использование диска (%)
-	UsedDisk  uint64  // Использовано диска (байты)
-	TotalDisk uint64  // Всего диска (байты)
+	Time            time.Time      `json:"time"`                // Время замера
+	CPU             float64        `json:"cpu"`                 // Использование CPU (%)
+	Cores           []float64      `json:"cores"`               // Использование отдельных ядер CPU (%)
+	Load1           float64        `json:"load1"`               // Средняя загрузка за 1 минуту
+	Load5           float64        `json:"load5"`               // Средняя загрузка за 5 минут
+	Load15          float64        `json:"load15"`              // Средняя загрузка за 15 минут
+	ContextSwitches float64        `json:"context_switches"`    // Переключения контекста в секунду
+	Interrupts      float64        `json:"interrupts"`          // Прерывания в секунду
+	Memory          float64        `json:"memory"`              // Использование памяти (%)
+	UsedMem         uint64         `json:"used_mem"`            // Использовано памяти (байты)
+	TotalMem        uint64         `json:"total_mem"`           // Всего памяти (байты)
+	Swap            float64        `json:"swap"`                // Использование swap (%)
+	UsedSwap        uint64         `json:"used_swap"`           // Использовано swap (байты)
+	TotalSwap       uint64         `json:"total_swap"`          // Всего swap (байты)
+	DiskUsage       float64        `json:"disk_usage"`          // Использование диска (%)
+	UsedDisk        uint64         `json:"used_disk"`           // Использовано диска (байты)
+	TotalDisk       uint64         `json:"total_disk"`          // Всего диска (байты)
+	Mounts          []MountStats   `json:"mounts,omitempty"`    // Использование отдельных точек монтирования
+	DiskIO          []DiskIOStats  `json:"disk_io,omitempty"`   // Пропускная способность блочных устройств
+	NetIO           []NetIOStats   `json:"net_io,omitempty"`    // Пропускная способность сетевых интерфейсов
+	Processes       []ProcessStats `json:"processes,omitempty"` // Процессы с наибольшим потреблением ресурсов
+	Target          *TargetStats   `json:"target,omitempty"`    // Отслеживаемый процесс, nil если не задан
+	Cgroup          *CgroupStats   `json:"cgroup,omitempty"`    // Ресурсы собственной cgroup (контейнера), nil если не учитывается
+}
+
+// Options содержит дополнительные параметры монитора
+type Options struct {
+	ProcFS    string         // Корень procfs, из которого читаются метрики
+	DiskPath  string         // Путь, для которого собирается статистика диска
+	Mounts    MountFilter    // Фильтр отображаемых точек монтирования
+	Processes ProcessOptions // Параметры списка процессов
+	Target    TargetOptions  // Отслеживаемый процесс
+	CgroupFS  string         // Корень иерархии cgroup
+	Cgroup    string         // Режим учета cgroup (auto, on, off, only)
+
+	Alerts          []AlertRule // Правила оповещений
+	AlertHysteresis float64     // Гистерезис правил (% от порога)
+	UntilAlert      bool        // Завершить мониторинг при первом срабатывании правила
+	Thresholds      Thresholds  // Пороги цветовой индикации
+	History         int         // Количество замеров в истории панели, 0 - без истории
+	RawBytes        bool        // Выводить в CSV байты и скорости числами без единиц измерения
+
+	Count    int           // Завершить мониторинг после Count замеров, 0 - без ограничения
+	Duration time.Duration // Завершить мониторинг через Duration, 0 - без ограничения
+
+	Interactive bool // Управление панелью с клавиатуры, если stdin и stdout - терминал
+
+	Log LogOptions // Журнал замеров с ротацией
 }
 
 // Monitor представляет монитор системных ресурсов
@@ -35,247 +74,499 @@ type Monitor struct {
 	ctx         context.Context
 	cancelFunc  context.CancelFunc
 	displayMode string
+	collector   *Collector
+	writer      io.Writer
+	csvLayout   *csvLayout // Набор колонок CSV, зафиксированный при выводе заголовка
+	alerts      *alertManager
+	untilAlert  bool
+	thresholds  Thresholds
+	history     *history // Скользящее окно замеров для спарклайнов, nil если выключено
+	rawBytes    bool
+	count       int           // Количество замеров до завершения, 0 - без ограничения
+	duration    time.Duration // Длительность мониторинга, 0 - без ограничения
+
+	// Состояние интерактивного режима панели
+	interactive bool
+	ui          *terminalUI  // nil, если интерактивный режим не запущен
+	panel       int          // Текущая панель (panelAll, panelSystem, ...)
+	paused      bool         // Сбор замеров приостановлен
+	last        *SystemStats // Последний замер для перерисовки
+
+	log     LogOptions
+	logFile *rotatingFile // Открытый журнал замеров, nil если журнал не ведется
 }
 
 // NewMonitor создает новый монитор системных ресурсов
-func NewMonitor(interval time.Duration, displayMode string) *Monitor {
+func NewMonitor(interval time.Duration, displayMode string, opts Options) *Monitor {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Monitor{
+	monitor := &Monitor{
 		interval:    interval,
 		ctx:         ctx,
 		cancelFunc:  cancel,
 		displayMode: displayMode,
+		collector:   NewCollector(opts),
+		writer:      os.Stdout,
+		untilAlert:  opts.UntilAlert,
+		thresholds:  opts.Thresholds,
+		rawBytes:    opts.RawBytes,
+		count:       opts.Count,
+		duration:    opts.Duration,
+		interactive: opts.Interactive,
+		log:         opts.Log,
+	}
+	if monitor.thresholds == (Thresholds{}) {
+		monitor.thresholds = DefaultThresholds
+	}
+	if opts.History > 0 {
+		monitor.history = newHistory(opts.History)
 	}
+	if len(opts.Alerts) > 0 {
+		monitor.alerts = newAlertManager(opts.Alerts, opts.AlertHysteresis)
+	}
+	return monitor
 }
 
-// NewCommand создает новую команду мониторинга ресурсов
-func NewCommand() *cobra.Command {
+// NewCommand создает новую команду мониторинга ресурсов.
+// Параметры журнала замеров по умолчанию берутся из раздела monitor конфигурации.
+func NewCommand(cfg *config.Config) *cobra.Command {
 	var (
-		interval    int
-		displayMode string
+		interval      int
+		displayMode   string
+		processFilter string
+		alertRules    []string
+		once          bool
+		logMaxSizeMB  int
+		opts          Options
 	)
 
+	// prepareOptions проверяет общие флаги и дополняет параметры монитора
+	prepareOptions := func() Options {
+		if err := checkInterval(time.Duration(interval) * time.Second); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+			os.Exit(1)
+		}
+		if err := ValidateSortBy(opts.Processes.SortBy); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+			os.Exit(1)
+		}
+		if processFilter != "" {
+			filter, err := regexp.Compile(processFilter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Неверное регулярное выражение фильтра процессов: %s\n", err)
+				os.Exit(1)
+			}
+			opts.Processes.Filter = filter
+		}
+		if err := ValidateCgroupMode(opts.Cgroup); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+			os.Exit(1)
+		}
+		if opts.Count < 0 || opts.Duration < 0 {
+			fmt.Fprintln(os.Stderr, "Ошибка: количество замеров и длительность не могут быть отрицательными")
+			os.Exit(1)
+		}
+		if once {
+			opts.Count = 1
+		}
+		if logMaxSizeMB < 0 || opts.Log.RotateEvery < 0 || opts.Log.MaxBackups < 0 || opts.Log.MaxAge < 0 {
+			fmt.Fprintln(os.Stderr, "Ошибка: параметры ротации журнала не могут быть отрицательными")
+			os.Exit(1)
+		}
+		opts.Log.MaxSize = int64(logMaxSizeMB) << 20
+		if opts.History < 0 {
+			fmt.Fprintln(os.Stderr, "Ошибка: размер истории не может быть отрицательным")
+			os.Exit(1)
+		}
+		if err := opts.Thresholds.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+			os.Exit(1)
+		}
+		for _, expr := range alertRules {
+			rule, err := ParseAlertRule(expr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
+				os.Exit(1)
+			}
+			opts.Alerts = append(opts.Alerts, rule)
+		}
+		if opts.UntilAlert && len(opts.Alerts) == 0 {
+			fmt.Fprintln(os.Stderr, "Ошибка: для --until-alert нужно задать хотя бы одно правило --alert")
+			os.Exit(1)
+		}
+		return opts
+	}
+
+	// run запускает монитор и завершает процесс с кодом 2 при срабатывании правила
+	run := func(start func() error) {
+		if err := start(); err != nil {
+			if errors.Is(err, ErrAlertTriggered) {
+				os.Exit(2)
+			}
+			fmt.Fprintf(os.Stderr, "Ошибка при запуске мониторинга: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	monitorCmd := &cobra.Command{
 		Use:   "monitor",
 		Short: "Мониторинг системных ресурсов",
-		Long:  "Мониторинг использования CPU, памяти и диска.",
+		Long:  "Мониторинг использования CPU (в том числе по ядрам), средней загрузки, памяти, дисков по точкам монтирования, дискового и сетевого ввода-вывода, а также лимитов контейнера (cgroup v1 и v2).",
+		Run: func(cmd *cobra.Command, args []string) {
+			monitor := NewMonitor(time.Duration(interval)*time.Second, displayMode, prepareOptions())
+			run(monitor.Start)
+		},
+	}
+
+	var output string
+	recordCmd := &cobra.Command{
+		Use:   "record",
+		Short: "Запись замеров в файл JSON Lines",
+		Long:  "Записывает замеры с отметками времени в файл JSON Lines (по одному замеру на строку) до прерывания или срабатывания правила --until-alert.",
 		Run: func(cmd *cobra.Command, args []string) {
-			monitor := NewMonitor(time.Duration(interval)*time.Second, displayMode)
-			if err := monitor.Start(); err != nil {
-				fmt.Fprintf(os.Stderr, "Ошибка при запуске мониторинга: %s\n", err)
+			monitor := NewMonitor(time.Duration(interval)*time.Second, displayRecord, prepareOptions())
+			if output != "" && output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Ошибка при создании файла: %s\n", err)
+					os.Exit(1)
+				}
+				defer file.Close()
+				monitor.writer = file
+				fmt.Fprintf(os.Stderr, "Запись сессии в %s, для остановки нажмите Ctrl+C\n", output)
+			}
+			run(monitor.Start)
+		},
+	}
+	recordCmd.Flags().StringVarP(&output, "output", "o", "", "Файл для записи сессии (по умолчанию stdout)")
+
+	var speed float64
+	replayCmd := &cobra.Command{
+		Use:   "replay [файл]",
+		Short: "Воспроизведение записанной сессии",
+		Long:  "Воспроизводит сессию, записанную командой monitor record, в выбранном режиме отображения с учетом правил оповещений.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if speed < 0 {
+				fmt.Fprintln(os.Stderr, "Ошибка: скорость воспроизведения не может быть отрицательной")
+				os.Exit(1)
+			}
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка при открытии файла: %s\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+
+			monitor := NewMonitor(time.Duration(interval)*time.Second, displayMode, prepareOptions())
+			run(func() error { return monitor.Replay(file, speed) })
+		},
+	}
+	replayCmd.Flags().Float64Var(&speed, "speed", 1, "Скорость воспроизведения (2 - вдвое быстрее, 0 - без пауз)")
+
+	var reportFormat string
+	reportCmd := &cobra.Command{
+		Use:   "report [файл]",
+		Short: "Сводка по записанной сессии",
+		Long:  "Выводит минимальные, средние, максимальные значения и 95-й перцентиль метрик записанной сессии.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка при открытии файла: %s\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+
+			samples, err := ReadSession(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка при чтении сессии: %s\n", err)
+				os.Exit(1)
+			}
+			if len(samples) == 0 {
+				fmt.Fprintln(os.Stderr, "Ошибка: сессия не содержит замеров")
+				os.Exit(1)
+			}
+
+			if err := NewSessionReport(samples).Write(os.Stdout, reportFormat); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %s\n", err)
 				os.Exit(1)
 			}
 		},
 	}
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", ReportFormatTable, "Формат отчета (table, markdown, json)")
 
-	monitorCmd.Flags().IntVarP(&interval, "interval", "i", 1, "Интервал обновления в секундах")
-	monitorCmd.Flags().StringVarP(&displayMode, "display", "d", "dashboard", "Режим отображения (dashboard, simple, csv)")
+	var listen string
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "HTTP-сервер с метриками для Prometheus",
+		Long:  "Собирает замеры в фоне и отдает их по HTTP: /metrics в текстовом формате Prometheus и /stats в формате JSON.",
+		Run: func(cmd *cobra.Command, args []string) {
+			monitor := NewMonitor(time.Duration(interval)*time.Second, displayServe, prepareOptions())
+			run(func() error { return monitor.Serve(listen) })
+		},
+	}
+	serveCmd.Flags().StringVar(&listen, "listen", DefaultListenAddr, "Адрес HTTP-сервера")
+
+	monitorCmd.AddCommand(recordCmd, replayCmd, reportCmd, serveCmd)
+
+	flags := monitorCmd.PersistentFlags()
+	flags.IntVarP(&interval, "interval", "i", cfg.Monitor.DefaultInterval, "Интервал обновления в секундах")
+	flags.IntVarP(&opts.Count, "count", "n", 0, "Завершить мониторинг после N замеров")
+	flags.DurationVar(&opts.Duration, "duration", 0, "Завершить мониторинг через указанное время, например 5m")
+	flags.BoolVar(&once, "once", false, "Вывести один замер и завершить работу")
+	monitorCmd.MarkFlagsMutuallyExclusive("count", "once")
+	flags.StringVarP(&displayMode, "display", "d", cfg.Monitor.DefaultDisplay, "Режим отображения (dashboard, simple, csv, json, ndjson)")
+	flags.StringVar(&opts.ProcFS, "procfs", DefaultProcFS, "Путь к procfs, из которого читаются метрики")
+	flags.StringVar(&opts.CgroupFS, "cgroupfs", DefaultCgroupFS, "Путь к иерархии cgroup")
+	flags.StringVar(&opts.Cgroup, "cgroup", CgroupAuto, "Учет ресурсов контейнера по cgroup: auto (если заданы лимиты), on, off, only (вместо CPU и памяти хоста)")
+	flags.StringVar(&opts.DiskPath, "disk-path", DefaultDiskPath, "Путь, для которого собирается статистика диска")
+	flags.StringSliceVar(&opts.Mounts.IncludeMounts, "include-mount", nil, "Показывать только эти точки монтирования (поддерживаются шаблоны, например /mnt/*)")
+	flags.StringSliceVar(&opts.Mounts.ExcludeMounts, "exclude-mount", nil, "Скрыть точки монтирования (поддерживаются шаблоны)")
+	flags.StringSliceVar(&opts.Mounts.IncludeFSTypes, "include-fstype", nil, "Показывать только эти типы файловых систем")
+	flags.StringSliceVar(&opts.Mounts.ExcludeFSTypes, "exclude-fstype", DefaultExcludeFSTypes, "Скрыть эти типы файловых систем")
+	flags.IntVar(&opts.Processes.Top, "top", 0, "Показать N процессов с наибольшим потреблением ресурсов")
+	flags.StringVar(&opts.Processes.SortBy, "sort", SortByCPU, "Сортировка процессов (cpu, mem, pid)")
+	flags.StringVar(&processFilter, "filter", "", "Регулярное выражение для фильтрации процессов по имени")
+	flags.IntVar(&opts.Target.PID, "pid", 0, "Отслеживать процесс с указанным PID")
+	flags.StringVar(&opts.Target.Command, "cmd", "", "Отслеживать процессы с указанным именем")
+	flags.BoolVar(&opts.Target.Children, "children", false, "Учитывать дочерние процессы отслеживаемого процесса")
+	monitorCmd.MarkFlagsMutuallyExclusive("pid", "cmd")
+	flags.StringArrayVar(&alertRules, "alert", nil, "Правило оповещения, например \"cpu > 90 for 30s\" или \"disk:/var > 85 then exec:notify.sh\" (можно указать несколько раз)")
+	flags.Float64Var(&opts.AlertHysteresis, "alert-hysteresis", DefaultAlertHysteresis, "Гистерезис снятия оповещения (% от порога)")
+	flags.BoolVar(&opts.UntilAlert, "until-alert", false, "Завершить мониторинг с кодом 2 при первом срабатывании правила")
+	flags.Float64Var(&opts.Thresholds.Warning, "warn-threshold", DefaultThresholds.Warning, "Порог предупреждения для цветовой индикации (%)")
+	flags.Float64Var(&opts.Thresholds.Critical, "crit-threshold", DefaultThresholds.Critical, "Критический порог для цветовой индикации (%)")
+	flags.BoolVar(&opts.RawBytes, "raw-bytes", false, "Выводить в CSV байты и скорости числами (без KB, MB, ...)")
+	flags.BoolVar(&opts.Interactive, "interactive", true, "Управление панелью с клавиатуры: панели, сортировка, пауза, интервал (только в терминале)")
+	logPath := ""
+	if cfg.Monitor.LogToFile {
+		logPath = cfg.Monitor.LogFilePath
+		if logPath == "" {
+			logPath = DefaultLogPath()
+		}
+	}
+	flags.StringVar(&opts.Log.Path, "log-file", logPath, "Дописывать замеры в файл журнала (JSON Lines) параллельно с выводом на экран")
+	flags.IntVar(&logMaxSizeMB, "log-max-size", cfg.Monitor.LogMaxSizeMB, "Размер файла журнала (МБ), после которого начинается новый файл, 0 - без ограничения")
+	flags.DurationVar(&opts.Log.RotateEvery, "log-rotate", cfg.Monitor.LogRotateEvery, "Начинать новый файл журнала с указанным периодом, например 24h, 0 - без ротации по времени")
+	flags.IntVar(&opts.Log.MaxBackups, "log-max-backups", cfg.Monitor.LogMaxBackups, "Сколько старых файлов журнала хранить, 0 - без ограничения")
+	flags.DurationVar(&opts.Log.MaxAge, "log-max-age", cfg.Monitor.LogMaxAge, "Удалять старые файлы журнала старше указанного времени, 0 - не удалять")
+	flags.IntVar(&opts.History, "history", DefaultHistorySize, "Количество замеров в истории (спарклайны) панели, 0 - без истории")
 
 	return monitorCmd
 }
 
-// Start запускает мониторинг системных ресурсов
+// Start запускает мониторинг системных ресурсов.
+// Первый замер выводится сразу, следующие - с интервалом монитора.
+// Мониторинг завершается по сигналу, после count замеров или по истечении duration,
+// а в интерактивном режиме панели - также по клавише q.
 func (m *Monitor) Start() error {
-	// Обрабатываем сигналы для корректного завершения
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	// Запускаем горутину для обработки сигналов
-	go func() {
-		<-sigCh
-		m.cancelFunc()
-	}()
-
-	// Запускаем сбор статистики
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
+	if err := checkInterval(m.interval); err != nil {
+		return err
+	}
+	m.watchSignals()
 
 	// Если используем dashboard, очищаем экран и скрываем курсор
 	if m.displayMode == "dashboard" {
-		fmt.Print("\033[?25l")       // Скрываем курсор
-		defer fmt.Print("\033[?25h") // Восстанавливаем курсор при выходе
+		// Терминал восстанавливается и при выходе по панике: отложенные вызовы выполняются при раскрутке стека
+		if m.interactive && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+			if err := m.startUI(); err != nil {
+				return err
+			}
+			defer m.stopUI()
+		}
+
+		fmt.Fprint(m.writer, "\033[?25l")       // Скрываем курсор
+		defer fmt.Fprint(m.writer, "\033[?25h") // Восстанавливаем курсор при выходе
+	}
+
+	// Ожидаем завершения команд оповещений перед выходом
+	if m.alerts != nil {
+		defer m.alerts.Wait()
+	}
+
+	closeLog, err := m.openLog()
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	var deadline <-chan time.Time
+	if m.duration > 0 {
+		timer := time.NewTimer(m.duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	// Клавиши и изменение размера терминала; nil-каналы вне интерактивного режима не срабатывают
+	var keys <-chan string
+	var resize <-chan os.Signal
+	if m.ui != nil {
+		keys, resize = m.ui.keys, m.ui.resize
+	}
+
+	// sample собирает и выводит замер; done равно true, если набрано count замеров
+	samples := 0
+	sample := func() (done bool, err error) {
+		stats, err := m.collectStats()
+		if err != nil {
+			return false, err
+		}
+		m.last = &stats
+		if err := m.handleStats(stats); err != nil {
+			return false, err
+		}
+		samples++
+		return m.count > 0 && samples >= m.count, nil
 	}
 
-	// Если выводим CSV, печатаем заголовок
-	if m.displayMode == "csv" {
-		fmt.Println("Time,CPU (%),Memory (%),Memory Used,Memory Total,Swap (%),Swap Used,Swap Total,Disk (%),Disk Used,Disk Total")
+	if done, err := sample(); done || err != nil {
+		return err
 	}
 
+	// Запускаем сбор статистики
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-m.ctx.Done():
 			return nil
+		case <-deadline:
+			return nil
+		case key := <-keys:
+			interval := m.interval
+			if m.handleKey(key) {
+				return nil
+			}
+			if m.interval != interval {
+				ticker.Reset(m.interval)
+			}
+			m.redraw()
+		case <-resize:
+			m.redraw()
 		case <-ticker.C:
-			stats, err := m.collectStats()
-			if err != nil {
-				return err
+			if m.paused {
+				continue
 			}
-
-			switch m.displayMode {
-			case "dashboard":
-				m.displayDashboard(stats)
-			case "simple":
-				m.displaySimple(stats)
-			case "csv":
-				m.displayCSV(stats)
-			default:
-				m.displayDashboard(stats)
+			if done, err := sample(); done || err != nil {
+				return err
 			}
 		}
 	}
 }
 
-// collectStats собирает статистику системных ресурсов
-func (m *Monitor) collectStats() (SystemStats, error) {
-	var stats SystemStats
-	var memStats runtime.MemStats
-
-	// Заполняем статистику по памяти Go
-	runtime.ReadMemStats(&memStats)
-
-	// В реальном приложении здесь был бы код для сбора системных метрик
-	// с использованием библиотек, например, github.com/shirou/gopsutil
-
-	// Для примера используем фиктивные данные
-	stats.CPU = 25.5
-	stats.Memory = 60.2
-	stats.UsedMem = 4 * 1024 * 1024 * 1024  // 4 GB
-	stats.TotalMem = 8 * 1024 * 1024 * 1024 // 8 GB
-	stats.Swap = 15.0
-	stats.UsedSwap = 1 * 1024 * 1024 * 1024  // 1 GB
-	stats.TotalSwap = 4 * 1024 * 1024 * 1024 // 4 GB
-	stats.DiskUsage = 45.0
-	stats.UsedDisk = 200 * 1024 * 1024 * 1024  // 200 GB
-	stats.TotalDisk = 500 * 1024 * 1024 * 1024 // 500 GB
-
-	return stats, nil
+// checkInterval проверяет, что интервал между замерами положительный
+func checkInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("интервал обновления должен быть положительным: %s", interval)
+	}
+	return nil
 }
 
-// displayDashboard отображает статистику в виде интерактивной панели
-func (m *Monitor) displayDashboard(stats SystemStats) {
-	// Очищаем экран
-	fmt.Print("\033[H\033[2J")
-
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+// startUI запускает интерактивный режим панели.
+// Вывод переводится на \r\n, так как в raw-режиме терминал не возвращает каретку.
+func (m *Monitor) startUI() error {
+	ui, err := startTerminalUI()
 	if err != nil {
-		width = 80 // Используем значение по умолчанию
-	}
-
-	// Выводим время
-	currentTime := time.Now().Format("15:04:05")
-	fmt.Printf("\n %s | DevHelper System Monitor\n\n", currentTime)
-
-	// Создаем и настраиваем таблицу
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Ресурс", "Использование", "Процент", "Детали"})
-
-	// Добавляем данные в таблицу
-	cpuColor := getColorByPercent(stats.CPU)
-	memColor := getColorByPercent(stats.Memory)
-	swapColor := getColorByPercent(stats.Swap)
-	diskColor := getColorByPercent(stats.DiskUsage)
-
-	t.AppendRow(table.Row{
-		"CPU",
-		renderProgressBar(stats.CPU, width/3),
-		cpuColor(fmt.Sprintf("%.1f%%", stats.CPU)),
-		fmt.Sprintf("%d ядер", runtime.NumCPU()),
-	})
-
-	t.AppendRow(table.Row{
-		"Memory",
-		renderProgressBar(stats.Memory, width/3),
-		memColor(fmt.Sprintf("%.1f%%", stats.Memory)),
-		fmt.Sprintf("%s / %s", formatBytes(stats.UsedMem), formatBytes(stats.TotalMem)),
-	})
-
-	t.AppendRow(table.Row{
-		"Swap",
-		renderProgressBar(stats.Swap, width/3),
-		swapColor(fmt.Sprintf("%.1f%%", stats.Swap)),
-		fmt.Sprintf("%s / %s", formatBytes(stats.UsedSwap), formatBytes(stats.TotalSwap)),
-	})
-
-	t.AppendRow(table.Row{
-		"Disk",
-		renderProgressBar(stats.DiskUsage, width/3),
-		diskColor(fmt.Sprintf("%.1f%%", stats.DiskUsage)),
-		fmt.Sprintf("%s / %s", formatBytes(stats.UsedDisk), formatBytes(stats.TotalDisk)),
-	})
-
-	t.SetStyle(table.StyleLight)
-	t.Render()
-
-	fmt.Println("\nНажмите Ctrl+C для выхода")
+		return err
+	}
+	m.ui = ui
+	m.writer = crlfWriter{w: m.writer}
+	if m.alerts != nil {
+		m.alerts.stderr = crlfWriter{w: m.alerts.stderr}
+	}
+	return nil
 }
 
-// displaySimple отображает статистику в простом формате
-func (m *Monitor) displaySimple(stats SystemStats) {
-	currentTime := time.Now().Format("15:04:05")
-	fmt.Printf("%s | CPU: %.1f%% | Memory: %.1f%% (%s/%s) | Disk: %.1f%% (%s/%s)\n",
-		currentTime,
-		stats.CPU,
-		stats.Memory, formatBytes(stats.UsedMem), formatBytes(stats.TotalMem),
-		stats.DiskUsage, formatBytes(stats.UsedDisk), formatBytes(stats.TotalDisk),
-	)
+// stopUI восстанавливает терминал и вывод после интерактивного режима
+func (m *Monitor) stopUI() {
+	if writer, ok := m.writer.(crlfWriter); ok {
+		m.writer = writer.w
+	}
+	if m.alerts != nil {
+		if writer, ok := m.alerts.stderr.(crlfWriter); ok {
+			m.alerts.stderr = writer.w
+		}
+	}
+	m.ui.close()
+	m.ui = nil
 }
 
-// displayCSV отображает статистику в формате CSV
-func (m *Monitor) displayCSV(stats SystemStats) {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s,%.1f,%.1f,%s,%s,%.1f,%s,%s,%.1f,%s,%s\n",
-		currentTime,
-		stats.CPU,
-		stats.Memory, formatBytes(stats.UsedMem), formatBytes(stats.TotalMem),
-		stats.Swap, formatBytes(stats.UsedSwap), formatBytes(stats.TotalSwap),
-		stats.DiskUsage, formatBytes(stats.UsedDisk), formatBytes(stats.TotalDisk),
-	)
+// watchSignals отменяет контекст монитора при получении SIGINT или SIGTERM
+func (m *Monitor) watchSignals() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sigCh:
+			m.cancelFunc()
+		case <-m.ctx.Done():
+		}
+		signal.Stop(sigCh)
+	}()
 }
 
-// renderProgressBar создает строку прогресс-бара
-func renderProgressBar(percent float64, width int) string {
-	if width < 10 {
-		width = 10
+// handleStats проверяет правила оповещений и выводит замер в выбранном режиме.
+// Возвращает ErrAlertTriggered, если сработало правило и задан режим --until-alert.
+func (m *Monitor) handleStats(stats SystemStats) error {
+	if m.history != nil {
+		m.history.add(stats)
 	}
 
-	completed := int(percent / 100 * float64(width))
-	if completed > width {
-		completed = width
+	var events []AlertEvent
+	if m.alerts != nil {
+		events = m.alerts.Evaluate(stats, stats.Time)
 	}
 
-	bar := "["
-	for i := 0; i < width; i++ {
-		if i < completed {
-			bar += "="
-		} else {
-			bar += " "
+	switch m.displayMode {
+	case "dashboard":
+		m.displayDashboard(stats)
+	case "simple":
+		m.displaySimple(stats)
+	case "csv":
+		m.displayCSV(stats)
+	case "json":
+		if err := m.displayJSON(stats); err != nil {
+			return err
+		}
+	case "ndjson", displayRecord:
+		if err := m.writeSample(stats); err != nil {
+			return err
 		}
+	case displayServe:
+		// Замеры отдаются по HTTP, на экран ничего не выводится
+	default:
+		m.displayDashboard(stats)
 	}
-	bar += "]"
-	return bar
-}
 
-// formatBytes форматирует байты в читаемый формат
-func formatBytes(bytes uint64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
+	if m.logFile != nil {
+		if err := m.writeLog(stats); err != nil {
+			return err
+		}
 	}
-	div, exp := uint64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+
+	if m.alerts != nil {
+		m.alerts.Notify(events)
+		if m.untilAlert && hasFiring(events) {
+			m.alerts.Wait()
+			return ErrAlertTriggered
+		}
 	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return nil
 }
 
-// getColorByPercent возвращает функцию цвета в зависимости от процента
-func getColorByPercent(percent float64) func(a ...interface{}) string {
-	if percent >= 90 {
-		return color.New(color.FgRed).SprintFunc()
-	} else if percent >= 70 {
-		return color.New(color.FgYellow).SprintFunc()
+// collectStats собирает статистику системных ресурсов
+func (m *Monitor) collectStats() (SystemStats, error) {
+	return m.collector.Collect()
+}
+
+// hasFiring проверяет, есть ли среди событий срабатывание правила
+func hasFiring(events []AlertEvent) bool {
+	for _, event := range events {
+		if event.State == AlertFiring {
+			return true
+		}
 	}
-	return color.New(color.FgGreen).SprintFunc()
+	return false
 }
@@ -3,27 +3,40 @@ package monitor
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMonitor(t *testing.T) {
 	interval := 1 * time.Second
 	displayMode := "dashboard"
 
-	monitor := NewMonitor(interval, displayMode)
+	monitor := NewMonitor(interval, displayMode, Options{})
 
 	assert.NotNil(t, monitor)
 	assert.Equal(t, interval, monitor.interval)
 	assert.Equal(t, displayMode, monitor.displayMode)
 	assert.NotNil(t, monitor.ctx)
 	assert.NotNil(t, monitor.cancelFunc)
+	assert.NotNil(t, monitor.collector)
+	assert.Equal(t, DefaultProcFS, monitor.collector.procRoot)
+	assert.Equal(t, DefaultDiskPath, monitor.collector.diskPath)
+	assert.Equal(t, DefaultThresholds, monitor.thresholds)
+	assert.Nil(t, monitor.alerts)
 }
 
 func TestMonitor_CollectStats(t *testing.T) {
-	monitor := NewMonitor(1*time.Second, "simple")
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	monitor := NewMonitor(1*time.Second, "simple", Options{})
 
 	stats, err := monitor.collectStats()
 
@@ -33,6 +46,13 @@ func TestMonitor_CollectStats(t *testing.T) {
 	assert.GreaterOrEqual(t, stats.CPU, 0.0)
 	assert.LessOrEqual(t, stats.CPU, 100.0)
 
+	assert.NotEmpty(t, stats.Cores)
+	for _, core := range stats.Cores {
+		assert.GreaterOrEqual(t, core, 0.0)
+		assert.LessOrEqual(t, core, 100.0)
+	}
+	assert.GreaterOrEqual(t, stats.Load1, 0.0)
+
 	assert.GreaterOrEqual(t, stats.Memory, 0.0)
 	assert.LessOrEqual(t, stats.Memory, 100.0)
 
@@ -55,6 +75,57 @@ func TestMonitor_CollectStats(t *testing.T) {
 	assert.LessOrEqual(t, stats.UsedDisk, stats.TotalDisk)
 }
 
+func TestMonitor_StartUntilAlert(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	rule, err := ParseAlertRule("mem >= 0")
+	require.NoError(t, err)
+	monitor := NewMonitor(10*time.Millisecond, "simple", Options{Alerts: []AlertRule{rule}, UntilAlert: true})
+	var buf bytes.Buffer
+	monitor.writer = &buf
+	monitor.alerts.stderr = &buf
+
+	err = monitor.Start()
+	assert.ErrorIs(t, err, ErrAlertTriggered)
+	assert.Contains(t, buf.String(), "[FIRING] mem >= 0")
+}
+
+func TestMonitor_StartBounded(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		samples int
+	}{
+		{name: "Количество замеров", opts: Options{Count: 3}, samples: 3},
+		// Первый замер выводится сразу, не дожидаясь интервала
+		{name: "Один замер", opts: Options{Count: 1}, samples: 1},
+		{name: "Длительность", opts: Options{Duration: 50 * time.Millisecond}, samples: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval := 10 * time.Millisecond
+			if tt.opts.Duration > 0 {
+				interval = time.Hour
+			}
+			monitor := NewMonitor(interval, "ndjson", tt.opts)
+			var buf bytes.Buffer
+			monitor.writer = &buf
+
+			require.NoError(t, monitor.Start())
+			samples, err := ReadSession(&buf)
+			require.NoError(t, err)
+			assert.Len(t, samples, tt.samples)
+		})
+	}
+}
+
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -149,28 +220,118 @@ func TestRenderProgressBar(t *testing.T) {
 }
 
 func TestDisplayModes(t *testing.T) {
+	stats := SystemStats{
+		CPU:             50,
+		Cores:           []float64{40, 60},
+		Load1:           0.5,
+		Load5:           1.25,
+		Load15:          2,
+		ContextSwitches: 1500,
+		Interrupts:      300,
+		Memory:          50,
+		UsedMem:         4 * 1024 * 1024 * 1024,
+		TotalMem:        8 * 1024 * 1024 * 1024,
+		Swap:            25,
+		UsedSwap:        1 * 1024 * 1024 * 1024,
+		TotalSwap:       4 * 1024 * 1024 * 1024,
+		DiskUsage:       40,
+		UsedDisk:        200 * 1024 * 1024 * 1024,
+		TotalDisk:       500 * 1024 * 1024 * 1024,
+		Mounts: []MountStats{
+			{MountPoint: "/", FSType: "ext4", Usage: 40, Used: 200 * 1024 * 1024 * 1024, Total: 500 * 1024 * 1024 * 1024, InodeUsage: 12.5},
+			{MountPoint: "/var", FSType: "xfs", Usage: 90, Used: 90 * 1024 * 1024 * 1024, Total: 100 * 1024 * 1024 * 1024, InodeUsage: 3},
+		},
+		DiskIO: []DiskIOStats{
+			{Device: "sda", ReadBytes: 2 * 1024 * 1024, WriteBytes: 512 * 1024, ReadOps: 120, WriteOps: 30.5},
+		},
+		NetIO: []NetIOStats{
+			{Interface: "eth0", RxBytes: 1536, TxBytes: 100, RxErrors: 3, TxErrors: 1},
+		},
+		Processes: []ProcessStats{
+			{PID: 4242, Name: "postgres", State: "S", CPU: 75.5, RSS: 256 * 1024 * 1024, Memory: 3.1, Threads: 8},
+		},
+		Target: &TargetStats{
+			Name: "PID 4242 + children", PIDs: []int{4242, 4243}, CPU: 80, RSS: 300 * 1024 * 1024, Memory: 3.7,
+			Threads: 10, FDs: 64, ReadBytes: 10 * 1024 * 1024, WriteBytes: 2048, ReadRate: 4096, WriteRate: 0,
+		},
+	}
+
 	tests := []struct {
 		name        string
 		displayMode string
 		validate    func(t *testing.T, output string)
 	}{
+		{
+			name:        "Dashboard mode",
+			displayMode: "dashboard",
+			validate: func(t *testing.T, output string) {
+				assert.Contains(t, output, "CPU0")
+				assert.Contains(t, output, "CPU1")
+				assert.Contains(t, output, "0.50 / 1.25 / 2.00")
+				assert.Contains(t, output, "ctx: 1.5k/s, intr: 300/s")
+				assert.Contains(t, output, "4.0 GB / 8.0 GB")
+				assert.Contains(t, output, "/var")
+				assert.Contains(t, output, "90.0 GB / 100.0 GB, xfs, inodes: 3.0%")
+				assert.Contains(t, output, "Disk I/O")
+				assert.Contains(t, output, "2.0 MB/s")
+				assert.Contains(t, output, "30.5")
+				assert.Contains(t, output, "Network")
+				assert.Contains(t, output, "1.5 KB/s")
+				assert.Contains(t, output, "Processes")
+				assert.Contains(t, output, "postgres")
+				assert.Contains(t, output, "256.0 MB")
+				assert.Contains(t, output, "Process: PID 4242 + children")
+				assert.Contains(t, output, "4242 4243")
+				assert.Contains(t, output, "300.0 MB (3.7%)")
+				assert.Contains(t, output, "4.0 KB/s (всего 10.0 MB)")
+			},
+		},
 		{
 			name:        "Simple mode",
 			displayMode: "simple",
 			validate: func(t *testing.T, output string) {
-				assert.Contains(t, output, "CPU:")
-				assert.Contains(t, output, "Memory:")
-				assert.Contains(t, output, "Disk:")
+				assert.Contains(t, output, "CPU: 50.0% [40.0 60.0]")
+				assert.Contains(t, output, "Load: 0.50 1.25 2.00")
+				assert.Contains(t, output, "Memory: 50.0% (4.0 GB/8.0 GB)")
+				assert.Contains(t, output, "Disk: 40.0% (200.0 GB/500.0 GB)")
+				assert.Contains(t, output, "Ctx: 1.5k/s | Intr: 300/s")
+				assert.Contains(t, output, "4242 postgres         CPU:  75.5% RSS: 256.0 MB (3.1%) Threads: 8")
+				assert.Contains(t, output, "PID 4242 + children: PID 4242 4243 | CPU: 80.0% | RSS: 300.0 MB | Threads: 10 | FDs: 64 | IO: R 4.0 KB/s W 0 B/s")
 			},
 		},
 		{
 			name:        "CSV mode",
 			displayMode: "csv",
 			validate: func(t *testing.T, output string) {
-				// Проверяем, что вывод разделен запятыми
-				assert.Contains(t, output, ",")
-				// Обычно в CSV должно быть 10+ полей
-				assert.Greater(t, len(bytes.Split([]byte(output), []byte(","))), 10)
+				records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+				assert.NoError(t, err)
+				// Заголовок выводится один раз
+				assert.Len(t, records, 3)
+				assert.Equal(t, "Time", records[0][0])
+				assert.Equal(t, "CPU1 (%)", records[0][17])
+				assert.Equal(t, "Disk /var (%)", records[0][22])
+				assert.Equal(t, "Inodes /var (%)", records[0][25])
+				assert.Equal(t, "IO sda Read/s", records[0][26])
+				assert.Equal(t, "Net eth0 TX Errors", records[0][33])
+				assert.Equal(t, "Target PIDs", records[0][34])
+				assert.Equal(t, "Target Write Total", records[0][len(records[0])-1])
+				assert.Equal(t, len(records[0]), len(records[1]))
+				assert.Equal(t, "50.0", records[1][1])
+				assert.Equal(t, "4.0 GB", records[1][3])
+				assert.Equal(t, "0.50", records[1][11])
+				assert.Equal(t, "1500.0", records[1][14])
+				assert.Equal(t, "60.0", records[1][17])
+				assert.Equal(t, "90.0", records[1][22])
+				assert.Equal(t, "100.0 GB", records[1][24])
+				assert.Equal(t, "3.0", records[1][25])
+				assert.Equal(t, "2.0 MB/s", records[1][26])
+				assert.Equal(t, "30.5", records[1][29])
+				assert.Equal(t, "1.5 KB/s", records[1][30])
+				assert.Equal(t, "1", records[1][33])
+				assert.Equal(t, "4242 4243", records[1][34])
+				assert.Equal(t, "80.0", records[1][35])
+				assert.Equal(t, "64", records[1][38])
+				assert.Equal(t, "2.0 KB", records[1][len(records[1])-1])
 			},
 		},
 	}
@@ -183,25 +344,132 @@ func TestDisplayModes(t *testing.T) {
 				displayMode: tt.displayMode,
 				ctx:         context.Background(),
 				cancelFunc:  func() {},
+				writer:      &buf,
 			}
 
-			stats, _ := monitor.collectStats()
-
-			_ = stats
-
-			// Вместо вызова методов напрямую, создаем тестовые строки
-			var output string
 			switch tt.displayMode {
+			case "dashboard":
+				monitor.displayDashboard(stats)
 			case "simple":
-				currentTime := time.Now().Format("15:04:05")
-				output = currentTime + " | CPU: 50.0% | Memory: 50.0% (4.0 GB/8.0 GB) | Disk: 50.0% (200.0 GB/500.0 GB)\n"
+				monitor.displaySimple(stats)
 			case "csv":
-				currentTime := time.Now().Format("2006-01-02 15:04:05")
-				output = currentTime + ",50.0,50.0,4.0 GB,8.0 GB,50.0,1.0 GB,4.0 GB,50.0,200.0 GB,500.0 GB\n"
+				monitor.displayCSV(stats)
+				monitor.displayCSV(stats)
 			}
 
-			buf.WriteString(output)
 			tt.validate(t, buf.String())
 		})
 	}
 }
+
+func TestDisplayCgroup(t *testing.T) {
+	stats := SystemStats{
+		CPU:   10,
+		Cores: []float64{10},
+		Cgroup: &CgroupStats{
+			Version: 2, Path: "/docker/abc", CPU: 75, CPULimit: 2, Memory: 25,
+			MemoryUsed: 256 * 1024 * 1024, MemoryLimit: 1024 * 1024 * 1024,
+			Periods: 120, Throttled: 15, ThrottledTime: 0.9, ThrottledPercent: 25,
+		},
+	}
+
+	newMonitor := func(buf *bytes.Buffer) *Monitor {
+		return &Monitor{ctx: context.Background(), cancelFunc: func() {}, writer: buf, thresholds: DefaultThresholds}
+	}
+
+	var buf bytes.Buffer
+	newMonitor(&buf).displayDashboard(stats)
+	assert.Contains(t, buf.String(), "Container: cgroup v2 /docker/abc")
+	assert.Contains(t, buf.String(), "2.00 CPU")
+	assert.Contains(t, buf.String(), "256.0 MB / 1.0 GB")
+	assert.Contains(t, buf.String(), "15 из 120 периодов, 0.9 с")
+
+	buf.Reset()
+	newMonitor(&buf).displaySimple(stats)
+	assert.Contains(t, buf.String(), "cgroup v2: CPU: 75.0% of 2.00 CPU | Memory: 25.0% (256.0 MB/1.0 GB) | Throttled: 15 (25.0%)")
+
+	// Без лимитов простой режим выводит то же, что и панель
+	buf.Reset()
+	unlimited := stats
+	unlimited.Cgroup = &CgroupStats{Version: 2, CPU: 10, MemoryUsed: 1024}
+	newMonitor(&buf).displaySimple(unlimited)
+	assert.Contains(t, buf.String(), "cgroup v2: CPU: 10.0% of без лимита | Memory: 0.0% (1.0 KB/без лимита)")
+
+	buf.Reset()
+	monitor := newMonitor(&buf)
+	monitor.displayCSV(stats)
+	stats.Cgroup = nil
+	monitor.displayCSV(stats)
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "Cgroup CPU (%)", records[0][len(records[0])-7])
+	assert.Equal(t, []string{"75.0", "2.00", "25.0", "256.0 MB", "1.0 GB", "15", "25.0"}, records[1][len(records[1])-7:])
+	// Колонки cgroup остаются пустыми, если метрики недоступны
+	assert.Equal(t, len(records[0]), len(records[2]))
+	assert.Equal(t, "", records[2][len(records[2])-1])
+}
+
+func TestDisplayJSON(t *testing.T) {
+	stats := SystemStats{
+		Time:     time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		CPU:      12.5,
+		UsedMem:  4 * 1024 * 1024 * 1024,
+		TotalMem: 8 * 1024 * 1024 * 1024,
+		NetIO:    []NetIOStats{{Interface: "eth0", RxBytes: 1536.5}},
+	}
+
+	for _, mode := range []string{"json", "ndjson"} {
+		t.Run(mode, func(t *testing.T) {
+			var buf bytes.Buffer
+			monitor := &Monitor{displayMode: mode, ctx: context.Background(), cancelFunc: func() {}, writer: &buf}
+			require.NoError(t, monitor.handleStats(stats))
+			require.NoError(t, monitor.handleStats(stats))
+
+			// Оба формата читаются потоковым декодером
+			samples, err := ReadSession(&buf)
+			require.NoError(t, err)
+			require.Len(t, samples, 2)
+			assert.Equal(t, stats.Time, samples[0].Time)
+			assert.Equal(t, uint64(4*1024*1024*1024), samples[0].UsedMem)
+			assert.Equal(t, 1536.5, samples[0].NetIO[0].RxBytes)
+		})
+	}
+
+	var buf bytes.Buffer
+	monitor := &Monitor{displayMode: "ndjson", ctx: context.Background(), cancelFunc: func() {}, writer: &buf}
+	require.NoError(t, monitor.handleStats(stats))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), `"time":"2025-01-01T12:00:00Z"`)
+	assert.Contains(t, buf.String(), `"used_mem":4294967296`)
+}
+
+func TestDisplayCSV_RawBytes(t *testing.T) {
+	stats := SystemStats{
+		CPU:      12.5,
+		UsedMem:  4 * 1024 * 1024 * 1024,
+		TotalMem: 8 * 1024 * 1024 * 1024,
+		NetIO:    []NetIOStats{{Interface: "eth0", RxBytes: 1536, TxBytes: 100}},
+	}
+
+	var buf bytes.Buffer
+	monitor := &Monitor{ctx: context.Background(), cancelFunc: func() {}, writer: &buf, rawBytes: true}
+	monitor.displayCSV(stats)
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "4294967296", records[1][3])
+	assert.Equal(t, "8589934592", records[1][4])
+	assert.Equal(t, "1536.0", records[1][len(records[1])-4])
+}
+
+func TestMonitor_NonPositiveInterval(t *testing.T) {
+	monitor := NewMonitor(0, "ndjson", Options{Count: 1})
+	var buf bytes.Buffer
+	monitor.writer = &buf
+
+	assert.ErrorContains(t, monitor.Start(), "интервал обновления должен быть положительным")
+	assert.ErrorContains(t, monitor.Serve("127.0.0.1:0"), "интервал обновления должен быть положительным")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,177 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultExcludeFSTypes типы файловых систем, которые не отображаются по умолчанию:
+// виртуальные и временные ФС, а также слои контейнеров
+var DefaultExcludeFSTypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devpts", "devtmpfs", "efivarfs", "fusectl", "hugetlbfs", "mqueue", "nsfs",
+	"overlay", "proc", "pstore", "ramfs", "rpc_pipefs", "securityfs", "selinuxfs",
+	"squashfs", "sysfs", "tmpfs", "tracefs",
+}
+
+// MountStats представляет статистику использования точки монтирования
+type MountStats struct {
+	MountPoint  string  `json:"mount_point"`  // Точка монтирования
+	Device      string  `json:"device"`       // Устройство
+	FSType      string  `json:"fs_type"`      // Тип файловой системы
+	Usage       float64 `json:"usage"`        // Использование места (%)
+	Used        uint64  `json:"used"`         // Использовано (байты)
+	Total       uint64  `json:"total"`        // Всего (байты)
+	InodeUsage  float64 `json:"inode_usage"`  // Использование inode (%)
+	UsedInodes  uint64  `json:"used_inodes"`  // Использовано inode
+	TotalInodes uint64  `json:"total_inodes"` // Всего inode
+}
+
+// MountFilter определяет, какие точки монтирования отображаются.
+// Шаблоны точек монтирования поддерживают синтаксис path.Match.
+type MountFilter struct {
+	IncludeMounts  []string // Показывать только эти точки монтирования
+	ExcludeMounts  []string // Скрывать эти точки монтирования
+	IncludeFSTypes []string // Показывать только эти типы ФС
+	ExcludeFSTypes []string // Скрывать эти типы ФС
+}
+
+// mountEntry представляет строку из /proc/self/mounts
+type mountEntry struct {
+	device     string
+	mountPoint string
+	fsType     string
+}
+
+// Match проверяет, проходит ли точка монтирования через фильтр.
+// Явно указанный в IncludeFSTypes тип отображается, даже если он есть в ExcludeFSTypes.
+func (f MountFilter) Match(mountPoint, fsType string) bool {
+	if len(f.IncludeMounts) > 0 && !matchMountPattern(f.IncludeMounts, mountPoint) {
+		return false
+	}
+	if matchMountPattern(f.ExcludeMounts, mountPoint) {
+		return false
+	}
+	if len(f.IncludeFSTypes) > 0 {
+		return slices.Contains(f.IncludeFSTypes, fsType)
+	}
+	return !slices.Contains(f.ExcludeFSTypes, fsType)
+}
+
+// collectMounts собирает статистику по отфильтрованным точкам монтирования
+func (c *Collector) collectMounts() ([]MountStats, error) {
+	entries, err := readMounts(c.procPath("self/mounts"))
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []MountStats
+	for _, entry := range entries {
+		if !c.mountFilter.Match(entry.mountPoint, entry.fsType) {
+			continue
+		}
+
+		usage, err := statFS(entry.mountPoint)
+		if err != nil {
+			// Точка монтирования может быть недоступна (нет прав, отключенный сетевой диск)
+			continue
+		}
+		if usage.Total == 0 {
+			continue
+		}
+
+		used := usage.Total - usage.Free
+		usedInodes := uint64(0)
+		if usage.Files >= usage.FilesFree {
+			usedInodes = usage.Files - usage.FilesFree
+		}
+		mounts = append(mounts, MountStats{
+			MountPoint:  entry.mountPoint,
+			Device:      entry.device,
+			FSType:      entry.fsType,
+			Usage:       percentOf(used, used+usage.Avail),
+			Used:        used,
+			Total:       usage.Total,
+			InodeUsage:  percentOf(usedInodes, usage.Files),
+			UsedInodes:  usedInodes,
+			TotalInodes: usage.Files,
+		})
+	}
+
+	return mounts, nil
+}
+
+// readMounts читает список точек монтирования.
+// Если точка смонтирована несколько раз, учитывается последнее (видимое) монтирование.
+func readMounts(path string) ([]mountEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var entries []mountEntry
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		entry := mountEntry{
+			device:     unescapeMountField(fields[0]),
+			mountPoint: unescapeMountField(fields[1]),
+			fsType:     fields[2],
+		}
+		if i, ok := index[entry.mountPoint]; ok {
+			entries[i] = entry
+			continue
+		}
+		index[entry.mountPoint] = len(entries)
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+// unescapeMountField раскодирует восьмеричные последовательности (\040 - пробел)
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, "\\") {
+		return field
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if code, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// matchMountPattern проверяет, соответствует ли точка монтирования одному из шаблонов
+func matchMountPattern(patterns []string, mountPoint string) bool {
+	for _, pattern := range patterns {
+		if pattern == mountPoint {
+			return true
+		}
+		if matched, err := path.Match(pattern, mountPoint); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMounts(t *testing.T) {
+	root := t.TempDir()
+	writeProcFile(t, root, "self/mounts", `/dev/sda1 / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid 0 0
+tmpfs /run tmpfs rw 0 0
+/dev/sdb1 /mnt/my\040disk ext4 rw 0 0
+/dev/sdc1 /run xfs rw 0 0
+broken
+`)
+
+	entries, err := readMounts(filepath.Join(root, "self/mounts"))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, mountEntry{device: "/dev/sda1", mountPoint: "/", fsType: "ext4"}, entries[0])
+	// Повторное монтирование перекрывает предыдущее
+	assert.Equal(t, mountEntry{device: "/dev/sdc1", mountPoint: "/run", fsType: "xfs"}, entries[2])
+	// Пробел в пути закодирован как \040
+	assert.Equal(t, "/mnt/my disk", entries[3].mountPoint)
+
+	_, err = readMounts(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestMountFilter_Match(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     MountFilter
+		mountPoint string
+		fsType     string
+		expected   bool
+	}{
+		{
+			name:       "Default exclude",
+			filter:     MountFilter{ExcludeFSTypes: DefaultExcludeFSTypes},
+			mountPoint: "/dev/shm",
+			fsType:     "tmpfs",
+			expected:   false,
+		},
+		{
+			name:       "Regular filesystem",
+			filter:     MountFilter{ExcludeFSTypes: DefaultExcludeFSTypes},
+			mountPoint: "/var",
+			fsType:     "ext4",
+			expected:   true,
+		},
+		{
+			name:       "Include fstype overrides exclude",
+			filter:     MountFilter{IncludeFSTypes: []string{"tmpfs"}, ExcludeFSTypes: DefaultExcludeFSTypes},
+			mountPoint: "/dev/shm",
+			fsType:     "tmpfs",
+			expected:   true,
+		},
+		{
+			name:       "Include fstype hides others",
+			filter:     MountFilter{IncludeFSTypes: []string{"xfs"}},
+			mountPoint: "/",
+			fsType:     "ext4",
+			expected:   false,
+		},
+		{
+			name:       "Include mount pattern",
+			filter:     MountFilter{IncludeMounts: []string{"/mnt/*"}},
+			mountPoint: "/mnt/data",
+			fsType:     "ext4",
+			expected:   true,
+		},
+		{
+			name:       "Include mount mismatch",
+			filter:     MountFilter{IncludeMounts: []string{"/", "/var"}},
+			mountPoint: "/home",
+			fsType:     "ext4",
+			expected:   false,
+		},
+		{
+			name:       "Exclude mount",
+			filter:     MountFilter{ExcludeMounts: []string{"/boot*"}},
+			mountPoint: "/boot",
+			fsType:     "vfat",
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Match(tt.mountPoint, tt.fsType))
+		})
+	}
+}
+
+func TestCollector_CollectMounts(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	root := t.TempDir()
+	writeProcFile(t, root, "self/mounts", "/dev/sda1 "+root+" ext4 rw 0 0\n"+
+		"tmpfs "+filepath.Join(root, "self")+" tmpfs rw 0 0\n"+
+		"/dev/sdb1 "+filepath.Join(root, "missing")+" ext4 rw 0 0\n")
+
+	mounts, err := NewCollector(Options{ProcFS: root}).collectMounts()
+	require.NoError(t, err)
+
+	// tmpfs исключен фильтром, недоступная точка монтирования пропущена
+	require.Len(t, mounts, 1)
+	mount := mounts[0]
+	assert.Equal(t, root, mount.MountPoint)
+	assert.Equal(t, "/dev/sda1", mount.Device)
+	assert.Equal(t, "ext4", mount.FSType)
+	assert.Greater(t, mount.Total, uint64(0))
+	assert.LessOrEqual(t, mount.Used, mount.Total)
+	assert.GreaterOrEqual(t, mount.Usage, 0.0)
+	assert.LessOrEqual(t, mount.Usage, 100.0)
+	assert.LessOrEqual(t, mount.UsedInodes, mount.TotalInodes)
+}
@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые порядки сортировки процессов
+const (
+	SortByCPU = "cpu"
+	SortByMem = "mem"
+	SortByPID = "pid"
+)
+
+// ProcessStats представляет статистику процесса
+type ProcessStats struct {
+	PID     int     `json:"pid"`     // Идентификатор процесса
+	Name    string  `json:"name"`    // Имя процесса
+	State   string  `json:"state"`   // Состояние (R, S, D, Z и т.д.)
+	CPU     float64 `json:"cpu"`     // Использование CPU (% одного ядра, может превышать 100)
+	RSS     uint64  `json:"rss"`     // Резидентная память (байты)
+	Memory  float64 `json:"memory"`  // Доля резидентной памяти от общей (%)
+	Threads int     `json:"threads"` // Количество потоков
+}
+
+// ProcessOptions определяет, какие процессы попадают в список
+type ProcessOptions struct {
+	Top    int            // Количество процессов в списке, 0 - не собирать
+	SortBy string         // Порядок сортировки: cpu, mem или pid
+	Filter *regexp.Regexp // Фильтр по имени процесса
+}
+
+// processInfo содержит данные процесса из /proc/[pid]/stat и /proc/[pid]/status
+type processInfo struct {
+	pid     int
+	ppid    int
+	name    string
+	state   string
+	ticks   uint64 // utime + stime
+	rss     uint64
+	threads int
+}
+
+// ValidateSortBy проверяет порядок сортировки процессов
+func ValidateSortBy(sortBy string) error {
+	switch sortBy {
+	case SortByCPU, SortByMem, SortByPID:
+		return nil
+	}
+	return fmt.Errorf("неизвестный порядок сортировки: %s (доступны: cpu, mem, pid)", sortBy)
+}
+
+// collectProcesses собирает список процессов с наибольшим потреблением ресурсов.
+// coreTicks - число тиков одного ядра с предыдущего замера (0 при первом замере).
+func (c *Collector) collectProcesses(infos []processInfo, coreTicks float64, memTotal uint64) []ProcessStats {
+	prevTicks := c.prevProc
+	c.prevProc = make(map[int]uint64, len(infos))
+
+	var processes []ProcessStats
+	for _, info := range infos {
+		c.prevProc[info.pid] = info.ticks
+
+		if c.processes.Filter != nil && !c.processes.Filter.MatchString(info.name) {
+			continue
+		}
+
+		process := ProcessStats{
+			PID:     info.pid,
+			Name:    info.name,
+			State:   info.state,
+			RSS:     info.rss,
+			Memory:  percentOf(info.rss, memTotal),
+			Threads: info.threads,
+		}
+		if prev, ok := prevTicks[info.pid]; ok && coreTicks > 0 && info.ticks >= prev {
+			process.CPU = float64(info.ticks-prev) / coreTicks * 100
+		}
+		processes = append(processes, process)
+	}
+
+	sortProcesses(processes, c.processes.SortBy)
+	if len(processes) > c.processes.Top {
+		processes = processes[:c.processes.Top]
+	}
+
+	return processes
+}
+
+// sortProcesses сортирует процессы по убыванию CPU или памяти либо по возрастанию PID
+func sortProcesses(processes []ProcessStats, sortBy string) {
+	sort.SliceStable(processes, func(i, j int) bool {
+		a, b := processes[i], processes[j]
+		switch sortBy {
+		case SortByMem:
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case SortByCPU, "":
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		}
+		return a.PID < b.PID
+	})
+}
+
+// readProcesses читает информацию обо всех процессах из procfs.
+// Процессы, завершившиеся во время чтения, пропускаются.
+func (c *Collector) readProcesses() ([]processInfo, error) {
+	entries, err := os.ReadDir(c.procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", c.procRoot, err)
+	}
+
+	var infos []processInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		info, err := readProcess(filepath.Join(c.procRoot, entry.Name()))
+		if err != nil {
+			continue
+		}
+		info.pid = pid
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// readProcess читает /proc/[pid]/stat и /proc/[pid]/status
+func readProcess(dir string) (processInfo, error) {
+	var info processInfo
+
+	data, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return info, err
+	}
+	if err := parseProcessStat(string(data), &info); err != nil {
+		return info, err
+	}
+
+	file, err := os.Open(filepath.Join(dir, "status"))
+	if err != nil {
+		return info, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Name":
+			info.name = value
+		case "VmRSS":
+			fields := strings.Fields(value)
+			if len(fields) > 0 {
+				rss, _ := strconv.ParseUint(fields[0], 10, 64)
+				info.rss = rss * 1024
+			}
+		case "Threads":
+			info.threads, _ = strconv.Atoi(value)
+		}
+	}
+
+	return info, scanner.Err()
+}
+
+// parseProcessStat разбирает /proc/[pid]/stat.
+// Имя процесса в скобках может содержать пробелы и скобки, поэтому
+// поля после него отсчитываются от последней закрывающей скобки.
+func parseProcessStat(data string, info *processInfo) error {
+	open := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return fmt.Errorf("неверный формат stat процесса")
+	}
+	info.name = data[open+1 : end]
+
+	// Поля после имени: state ppid pgrp session tty_nr tpgid flags
+	// minflt cminflt majflt cmajflt utime stime ...
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 13 {
+		return fmt.Errorf("неверный формат stat процесса")
+	}
+
+	info.state = fields[0]
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return fmt.Errorf("неверный PPID: %w", err)
+	}
+	info.ppid = ppid
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return fmt.Errorf("неверное значение utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return fmt.Errorf("неверное значение stime: %w", err)
+	}
+	info.ticks = utime + stime
+
+	return nil
+}
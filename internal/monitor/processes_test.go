@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestProcess создает файлы stat и status процесса в тестовом дереве procfs
+func writeTestProcess(t *testing.T, root string, pid, ppid int, name string, ticks, rssKB uint64, threads int) {
+	t.Helper()
+	writeProcFile(t, root, fmt.Sprintf("%d/stat", pid),
+		fmt.Sprintf("%d (%s) S %d 1 1 0 -1 4194304 100 0 0 0 %d 0 0 0 20 0 %d 0 100 1000 %d\n",
+			pid, name, ppid, ticks, threads, rssKB/4))
+	writeProcFile(t, root, fmt.Sprintf("%d/status", pid),
+		fmt.Sprintf("Name:\t%s\nState:\tS (sleeping)\nPPid:\t%d\nVmRSS:\t%8d kB\nThreads:\t%d\n",
+			name, ppid, rssKB, threads))
+}
+
+func TestParseProcessStat(t *testing.T) {
+	var info processInfo
+	err := parseProcessStat("42 (my (weird) proc) R 7 42 42 0 -1 0 0 0 0 0 150 50 0 0 20 0 3 0 1 2 3\n", &info)
+	require.NoError(t, err)
+	assert.Equal(t, "my (weird) proc", info.name)
+	assert.Equal(t, "R", info.state)
+	assert.Equal(t, 7, info.ppid)
+	assert.Equal(t, uint64(200), info.ticks)
+
+	assert.Error(t, parseProcessStat("42 no parens", &info))
+	assert.Error(t, parseProcessStat("42 (short) R 1 2", &info))
+	assert.Error(t, parseProcessStat("42 (bad) R x 42 42 0 -1 0 0 0 0 0 1 1", &info))
+}
+
+func TestCollector_CollectProcesses(t *testing.T) {
+	root := t.TempDir()
+	writeTestProcess(t, root, 1, 0, "init", 100, 4096, 1)
+	writeTestProcess(t, root, 200, 1, "postgres", 1000, 512000, 8)
+	writeTestProcess(t, root, 300, 1, "go build", 500, 102400, 12)
+	// Не процессы
+	writeProcFile(t, root, "stat", "cpu  1 1 1 1\n")
+	writeProcFile(t, root, "self/mounts", "")
+
+	collector := NewCollector(Options{ProcFS: root, Processes: ProcessOptions{Top: 2, SortBy: SortByCPU}})
+	memTotal := uint64(1024 * 1024 * 1024)
+
+	// Первый замер без загрузки CPU
+	infos, err := collector.readProcesses()
+	require.NoError(t, err)
+	require.Len(t, infos, 3)
+	processes := collector.collectProcesses(infos, 0, memTotal)
+	require.Len(t, processes, 2)
+	assert.Equal(t, 0.0, processes[0].CPU)
+
+	writeTestProcess(t, root, 1, 0, "init", 100, 4096, 1)
+	writeTestProcess(t, root, 200, 1, "postgres", 1050, 512000, 8)
+	writeTestProcess(t, root, 300, 1, "go build", 650, 102400, 12)
+
+	infos, err = collector.readProcesses()
+	require.NoError(t, err)
+	processes = collector.collectProcesses(infos, 100, memTotal)
+	require.Len(t, processes, 2)
+	assert.Equal(t, ProcessStats{
+		PID:     300,
+		Name:    "go build",
+		State:   "S",
+		CPU:     150,
+		RSS:     102400 * 1024,
+		Memory:  percentOf(102400*1024, memTotal),
+		Threads: 12,
+	}, processes[0])
+	assert.Equal(t, 200, processes[1].PID)
+	assert.InDelta(t, 50.0, processes[1].CPU, 0.001)
+
+	// Сортировка по памяти и фильтр по имени
+	collector.processes = ProcessOptions{Top: 10, SortBy: SortByMem, Filter: regexp.MustCompile("^(init|postgres)$")}
+	processes = collector.collectProcesses(infos, 100, memTotal)
+	require.Len(t, processes, 2)
+	assert.Equal(t, "postgres", processes[0].Name)
+	assert.Equal(t, "init", processes[1].Name)
+}
+
+func TestSortProcesses(t *testing.T) {
+	processes := []ProcessStats{
+		{PID: 3, CPU: 10, RSS: 100},
+		{PID: 1, CPU: 50, RSS: 10},
+		{PID: 2, CPU: 10, RSS: 1000},
+	}
+
+	sortProcesses(processes, SortByCPU)
+	assert.Equal(t, []int{1, 2, 3}, processPIDs(processes))
+
+	sortProcesses(processes, SortByMem)
+	assert.Equal(t, []int{2, 3, 1}, processPIDs(processes))
+
+	sortProcesses(processes, SortByPID)
+	assert.Equal(t, []int{1, 2, 3}, processPIDs(processes))
+}
+
+func TestValidateSortBy(t *testing.T) {
+	assert.NoError(t, ValidateSortBy("cpu"))
+	assert.NoError(t, ValidateSortBy("mem"))
+	assert.NoError(t, ValidateSortBy("pid"))
+	assert.Error(t, ValidateSortBy("name"))
+}
+
+// processPIDs возвращает PID процессов в порядке следования
+func processPIDs(processes []ProcessStats) []int {
+	pids := make([]int, len(processes))
+	for i, process := range processes {
+		pids[i] = process.PID
+	}
+	return pids
+}
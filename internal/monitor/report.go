@@ -0,0 +1,209 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Форматы отчета по сессии
+const (
+	ReportFormatTable    = "table"
+	ReportFormatMarkdown = "markdown"
+	ReportFormatJSON     = "json"
+)
+
+// Единицы измерения метрик отчета
+const (
+	unitPercent  = "%"
+	unitBytes    = "B"
+	unitByteRate = "B/s"
+	unitRate     = "1/s"
+	unitNone     = ""
+)
+
+// MetricSummary содержит сводные значения метрики за сессию
+type MetricSummary struct {
+	Name string  `json:"name"` // Название метрики
+	Unit string  `json:"unit"` // Единица измерения: %, B, B/s, 1/s или пусто
+	Min  float64 `json:"min"`
+	Avg  float64 `json:"avg"`
+	Max  float64 `json:"max"`
+	P95  float64 `json:"p95"` // 95-й перцентиль
+}
+
+// SessionReport содержит сводку по записанной сессии
+type SessionReport struct {
+	Samples int             `json:"samples"` // Количество замеров
+	Start   time.Time       `json:"start"`   // Время первого замера
+	End     time.Time       `json:"end"`     // Время последнего замера
+	Metrics []MetricSummary `json:"metrics"`
+}
+
+// metricSeries накапливает значения метрики в порядке первого появления
+type metricSeries struct {
+	names  []string
+	units  map[string]string
+	values map[string][]float64
+}
+
+// add добавляет значение метрики. Если counted равно false, метрика только
+// занимает место в порядке вывода, а значение не учитывается.
+func (s *metricSeries) add(name, unit string, value float64, counted bool) {
+	if _, ok := s.units[name]; !ok {
+		s.names = append(s.names, name)
+		s.units[name] = unit
+	}
+	if counted {
+		s.values[name] = append(s.values[name], value)
+	}
+}
+
+// NewSessionReport вычисляет сводку по замерам сессии.
+// Скорости (события, ввод-вывод) в первом замере всегда равны нулю, поэтому он для них не учитывается.
+func NewSessionReport(samples []SystemStats) SessionReport {
+	report := SessionReport{Samples: len(samples)}
+	if len(samples) == 0 {
+		return report
+	}
+	report.Start = samples[0].Time
+	report.End = samples[len(samples)-1].Time
+
+	series := &metricSeries{units: make(map[string]string), values: make(map[string][]float64)}
+	for i, stats := range samples {
+		rates := i > 0 || len(samples) == 1
+
+		series.add("CPU", unitPercent, stats.CPU, true)
+		series.add("Load 1m", unitNone, stats.Load1, true)
+		series.add("Memory", unitPercent, stats.Memory, true)
+		series.add("Memory used", unitBytes, float64(stats.UsedMem), true)
+		series.add("Swap", unitPercent, stats.Swap, true)
+		series.add("Disk", unitPercent, stats.DiskUsage, true)
+		series.add("Context switches", unitRate, stats.ContextSwitches, rates)
+		series.add("Interrupts", unitRate, stats.Interrupts, rates)
+		for _, mount := range stats.Mounts {
+			series.add("Disk "+mount.MountPoint, unitPercent, mount.Usage, true)
+		}
+		for _, io := range stats.DiskIO {
+			series.add("IO "+io.Device+" read", unitByteRate, io.ReadBytes, rates)
+			series.add("IO "+io.Device+" write", unitByteRate, io.WriteBytes, rates)
+		}
+		for _, io := range stats.NetIO {
+			series.add("Net "+io.Interface+" rx", unitByteRate, io.RxBytes, rates)
+			series.add("Net "+io.Interface+" tx", unitByteRate, io.TxBytes, rates)
+		}
+		if target := stats.Target; target != nil && len(target.PIDs) > 0 {
+			series.add("Process CPU", unitPercent, target.CPU, rates)
+			series.add("Process RSS", unitBytes, float64(target.RSS), true)
+			series.add("Process FDs", unitNone, float64(target.FDs), true)
+			series.add("Process read", unitByteRate, target.ReadRate, rates)
+			series.add("Process write", unitByteRate, target.WriteRate, rates)
+		}
+		if cgroup := stats.Cgroup; cgroup != nil {
+			series.add("Cgroup CPU", unitPercent, cgroup.CPU, rates)
+			series.add("Cgroup memory", unitPercent, cgroup.Memory, true)
+			series.add("Cgroup memory used", unitBytes, float64(cgroup.MemoryUsed), true)
+			series.add("Cgroup throttled", unitPercent, cgroup.ThrottledPercent, rates)
+		}
+	}
+
+	for _, name := range series.names {
+		if len(series.values[name]) == 0 {
+			continue
+		}
+		report.Metrics = append(report.Metrics, summarize(name, series.units[name], series.values[name]))
+	}
+	return report
+}
+
+// summarize вычисляет минимум, среднее, максимум и 95-й перцентиль значений
+func summarize(name, unit string, values []float64) MetricSummary {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	return MetricSummary{
+		Name: name,
+		Unit: unit,
+		Min:  sorted[0],
+		Avg:  sum / float64(len(sorted)),
+		Max:  sorted[len(sorted)-1],
+		P95:  percentile(sorted, 95),
+	}
+}
+
+// percentile возвращает перцентиль отсортированных значений методом ближайшего ранга
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Write выводит отчет в формате table, markdown или json
+func (r SessionReport) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return fmt.Errorf("не удалось сформировать JSON: %w", err)
+		}
+		return nil
+	case ReportFormatTable, ReportFormatMarkdown:
+	default:
+		return fmt.Errorf("неизвестный формат отчета: %s (доступны: table, markdown, json)", format)
+	}
+
+	fmt.Fprintf(w, "Замеров: %d, начало: %s, длительность: %s\n\n",
+		r.Samples, r.Start.Format("2006-01-02 15:04:05"), r.End.Sub(r.Start).Round(time.Second))
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Метрика", "Min", "Avg", "Max", "P95"})
+	for _, metric := range r.Metrics {
+		t.AppendRow(table.Row{
+			metric.Name,
+			formatMetricValue(metric.Min, metric.Unit),
+			formatMetricValue(metric.Avg, metric.Unit),
+			formatMetricValue(metric.Max, metric.Unit),
+			formatMetricValue(metric.P95, metric.Unit),
+		})
+	}
+
+	if format == ReportFormatMarkdown {
+		t.RenderMarkdown()
+		return nil
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+	return nil
+}
+
+// formatMetricValue форматирует значение метрики в соответствии с единицей измерения
+func formatMetricValue(value float64, unit string) string {
+	switch unit {
+	case unitPercent:
+		return fmt.Sprintf("%.1f%%", value)
+	case unitBytes:
+		return formatBytes(uint64(value))
+	case unitByteRate:
+		return formatByteRate(value)
+	case unitRate:
+		return formatRate(value) + "/s"
+	}
+	return fmt.Sprintf("%.2f", value)
+}
@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findSummary возвращает сводку метрики по имени
+func findSummary(t *testing.T, report SessionReport, name string) MetricSummary {
+	t.Helper()
+	for _, metric := range report.Metrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	require.Failf(t, "метрика не найдена", "%s", name)
+	return MetricSummary{}
+}
+
+func TestNewSessionReport(t *testing.T) {
+	samples, err := ReadSession(strings.NewReader(testSession(t)))
+	require.NoError(t, err)
+	// CPU процесса - разность счетчиков, в первом замере она всегда равна нулю
+	for i, cpu := range []float64{0, 40, 20} {
+		samples[i].Target.CPU = cpu
+	}
+
+	report := NewSessionReport(samples)
+	assert.Equal(t, 3, report.Samples)
+	assert.Equal(t, 2*time.Second, report.End.Sub(report.Start))
+
+	cpu := findSummary(t, report, "CPU")
+	assert.Equal(t, MetricSummary{Name: "CPU", Unit: unitPercent, Min: 10, Avg: 155.0 / 3, Max: 95, P95: 95}, cpu)
+
+	assert.Equal(t, 70.0, findSummary(t, report, "Disk /var").Max)
+	assert.Equal(t, 1024.0, findSummary(t, report, "Process RSS").Avg)
+
+	// Первый замер не учитывается для скоростей
+	read := findSummary(t, report, "IO sda read")
+	assert.Equal(t, 1024.0, read.Min)
+	assert.Equal(t, 2048.0, read.Max)
+	processCPU := findSummary(t, report, "Process CPU")
+	assert.Equal(t, 20.0, processCPU.Min)
+	assert.Equal(t, 30.0, processCPU.Avg)
+
+	assert.Equal(t, 0, NewSessionReport(nil).Samples)
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	assert.Equal(t, 95.0, percentile(values, 95))
+	assert.Equal(t, 100.0, percentile(values, 100))
+	assert.Equal(t, 1.0, percentile(values, 0))
+	assert.Equal(t, 7.0, percentile([]float64{7}, 95))
+	assert.Equal(t, 0.0, percentile(nil, 95))
+}
+
+func TestSessionReport_Write(t *testing.T) {
+	samples, err := ReadSession(strings.NewReader(testSession(t)))
+	require.NoError(t, err)
+	report := NewSessionReport(samples)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, ReportFormatTable))
+	assert.Contains(t, buf.String(), "Замеров: 3, начало: 2024-01-01 12:00:00, длительность: 2s")
+	assert.Contains(t, buf.String(), "51.7%")
+	assert.Contains(t, buf.String(), "2.0 KB/s")
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, ReportFormatMarkdown))
+	assert.Contains(t, buf.String(), "| CPU | 10.0% | 51.7% | 95.0% | 95.0% |")
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, ReportFormatJSON))
+	var decoded SessionReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Metrics, decoded.Metrics)
+
+	assert.Error(t, report.Write(&buf, "xml"))
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package monitor
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize подписывает канал на изменение размера терминала (SIGWINCH)
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package monitor
+
+import "os"
+
+// notifyResize на Windows не используется: сигнала об изменении размера консоли нет,
+// и новый размер учитывается при следующей перерисовке панели
+func notifyResize(ch chan<- os.Signal) {}
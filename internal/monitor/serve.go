@@ -0,0 +1,336 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultListenAddr адрес HTTP-сервера monitor serve по умолчанию
+const DefaultListenAddr = ":9100"
+
+// displayServe режим без вывода: замеры отдаются по HTTP
+const displayServe = "serve"
+
+// shutdownTimeout время на завершение активных запросов при остановке сервера
+const shutdownTimeout = 5 * time.Second
+
+// alertStatus состояние правила оповещения для экспорта
+type alertStatus struct {
+	Rule   string
+	Firing bool
+}
+
+// statsStore хранит последний замер для HTTP-обработчиков
+type statsStore struct {
+	mu     sync.RWMutex
+	stats  SystemStats
+	alerts []alertStatus
+	ready  bool
+}
+
+// set сохраняет замер и состояние правил
+func (s *statsStore) set(stats SystemStats, alerts []alertStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats, s.alerts, s.ready = stats, alerts, true
+}
+
+// get возвращает последний замер; ready равно false, пока замеров не было
+func (s *statsStore) get() (stats SystemStats, alerts []alertStatus, ready bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stats, s.alerts, s.ready
+}
+
+// Serve запускает HTTP-сервер с метриками в формате Prometheus (/metrics) и JSON (/stats).
+// Замеры собираются в фоне с интервалом монитора тем же сборщиком, что и для панели,
+// поэтому скорости не зависят от частоты опроса сервера.
+func (m *Monitor) Serve(addr string) error {
+	if err := checkInterval(m.interval); err != nil {
+		return err
+	}
+	m.watchSignals()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть адрес %s: %w", addr, err)
+	}
+	fmt.Fprintf(m.writer, "Метрики доступны по адресу http://%s/metrics\n", listener.Addr())
+
+	closeLog, err := m.openLog()
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer closeLog()
+
+	store := &statsStore{}
+	server := &http.Server{Handler: newServeMux(store), ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(ctx)
+	}
+
+	sample := func() error {
+		stats, err := m.collectStats()
+		if err != nil {
+			return err
+		}
+		err = m.handleStats(stats)
+		store.set(stats, m.alertStatuses())
+		return err
+	}
+
+	if err := sample(); err != nil {
+		shutdown()
+		return err
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			shutdown()
+			return nil
+		case err := <-errCh:
+			return fmt.Errorf("ошибка HTTP-сервера: %w", err)
+		case <-ticker.C:
+			if err := sample(); err != nil {
+				shutdown()
+				return err
+			}
+		}
+	}
+}
+
+// alertStatuses возвращает состояние всех правил оповещений
+func (m *Monitor) alertStatuses() []alertStatus {
+	if m.alerts == nil {
+		return nil
+	}
+	statuses := make([]alertStatus, 0, len(m.alerts.states))
+	for _, state := range m.alerts.states {
+		statuses = append(statuses, alertStatus{Rule: state.rule.Expr, Firing: state.firing})
+	}
+	return statuses
+}
+
+// newServeMux создает обработчики /metrics и /stats
+func newServeMux(store *statsStore) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		stats, alerts, ready := store.get()
+		if !ready {
+			http.Error(w, "замеры еще не собраны", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheus(w, stats, alerts)
+	})
+
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats, _, ready := store.get()
+		if !ready {
+			http.Error(w, "замеры еще не собраны", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><title>DevHelper Monitor</title></head><body>`+
+			`<h1>DevHelper Monitor</h1><p><a href="/metrics">Metrics</a> | <a href="/stats">Stats (JSON)</a></p></body></html>`)
+	})
+
+	return mux
+}
+
+// promSample значение метрики с метками (пары имя-значение)
+type promSample struct {
+	labels []string
+	value  float64
+}
+
+// promWriter выводит метрики в текстовом формате Prometheus
+type promWriter struct {
+	w io.Writer
+}
+
+// metric выводит описание метрики и ее значения. Метрики без значений пропускаются.
+func (p promWriter) metric(name, typ, help string, samples ...promSample) {
+	if len(samples) == 0 {
+		return
+	}
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, sample := range samples {
+		fmt.Fprintf(p.w, "%s%s %s\n", name, formatPromLabels(sample.labels), strconv.FormatFloat(sample.value, 'g', -1, 64))
+	}
+}
+
+// gauge выводит метрику типа gauge
+func (p promWriter) gauge(name, help string, samples ...promSample) {
+	p.metric(name, "gauge", help, samples...)
+}
+
+// counter выводит метрику типа counter
+func (p promWriter) counter(name, help string, samples ...promSample) {
+	p.metric(name, "counter", help, samples...)
+}
+
+// promValue создает значение метрики
+func promValue(v float64, labels ...string) promSample {
+	return promSample{labels: labels, value: v}
+}
+
+// formatPromLabels форматирует метки в виде {name="value",...}
+func formatPromLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, labels[i], escaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// writePrometheus выводит замер в текстовом формате Prometheus
+func writePrometheus(w io.Writer, stats SystemStats, alerts []alertStatus) {
+	p := promWriter{w: w}
+
+	p.gauge("devhelper_cpu_usage_percent", "Использование CPU (%)", promValue(stats.CPU))
+	cores := make([]promSample, len(stats.Cores))
+	for i, core := range stats.Cores {
+		cores[i] = promValue(core, "core", strconv.Itoa(i))
+	}
+	p.gauge("devhelper_cpu_core_usage_percent", "Использование ядра CPU (%)", cores...)
+	p.gauge("devhelper_load_average", "Средняя загрузка системы",
+		promValue(stats.Load1, "period", "1m"), promValue(stats.Load5, "period", "5m"), promValue(stats.Load15, "period", "15m"))
+	p.gauge("devhelper_context_switches_per_second", "Переключения контекста в секунду", promValue(stats.ContextSwitches))
+	p.gauge("devhelper_interrupts_per_second", "Прерывания в секунду", promValue(stats.Interrupts))
+
+	p.gauge("devhelper_memory_usage_percent", "Использование памяти (%)", promValue(stats.Memory))
+	p.gauge("devhelper_memory_used_bytes", "Использовано памяти (байты)", promValue(float64(stats.UsedMem)))
+	p.gauge("devhelper_memory_total_bytes", "Всего памяти (байты)", promValue(float64(stats.TotalMem)))
+	p.gauge("devhelper_swap_usage_percent", "Использование swap (%)", promValue(stats.Swap))
+	p.gauge("devhelper_swap_used_bytes", "Использовано swap (байты)", promValue(float64(stats.UsedSwap)))
+	p.gauge("devhelper_swap_total_bytes", "Всего swap (байты)", promValue(float64(stats.TotalSwap)))
+	p.gauge("devhelper_disk_usage_percent", "Использование диска по пути --disk-path (%)", promValue(stats.DiskUsage))
+	p.gauge("devhelper_disk_used_bytes", "Использовано диска по пути --disk-path (байты)", promValue(float64(stats.UsedDisk)))
+	p.gauge("devhelper_disk_total_bytes", "Всего диска по пути --disk-path (байты)", promValue(float64(stats.TotalDisk)))
+
+	var usage, used, size, inodes []promSample
+	for _, mount := range stats.Mounts {
+		labels := []string{"mountpoint", mount.MountPoint, "device", mount.Device, "fstype", mount.FSType}
+		usage = append(usage, promValue(mount.Usage, labels...))
+		used = append(used, promValue(float64(mount.Used), labels...))
+		size = append(size, promValue(float64(mount.Total), labels...))
+		inodes = append(inodes, promValue(mount.InodeUsage, labels...))
+	}
+	p.gauge("devhelper_filesystem_usage_percent", "Использование точки монтирования (%)", usage...)
+	p.gauge("devhelper_filesystem_used_bytes", "Использовано на точке монтирования (байты)", used...)
+	p.gauge("devhelper_filesystem_size_bytes", "Размер точки монтирования (байты)", size...)
+	p.gauge("devhelper_filesystem_inodes_usage_percent", "Использование inode точки монтирования (%)", inodes...)
+
+	var diskRead, diskWrite, readOps, writeOps []promSample
+	for _, io := range stats.DiskIO {
+		diskRead = append(diskRead, promValue(io.ReadBytes, "device", io.Device))
+		diskWrite = append(diskWrite, promValue(io.WriteBytes, "device", io.Device))
+		readOps = append(readOps, promValue(io.ReadOps, "device", io.Device))
+		writeOps = append(writeOps, promValue(io.WriteOps, "device", io.Device))
+	}
+	p.gauge("devhelper_disk_read_bytes_per_second", "Чтение с устройства (байт/с)", diskRead...)
+	p.gauge("devhelper_disk_write_bytes_per_second", "Запись на устройство (байт/с)", diskWrite...)
+	p.gauge("devhelper_disk_read_ops_per_second", "Операций чтения в секунду", readOps...)
+	p.gauge("devhelper_disk_write_ops_per_second", "Операций записи в секунду", writeOps...)
+
+	var rx, tx, rxErrors, txErrors []promSample
+	for _, io := range stats.NetIO {
+		rx = append(rx, promValue(io.RxBytes, "interface", io.Interface))
+		tx = append(tx, promValue(io.TxBytes, "interface", io.Interface))
+		rxErrors = append(rxErrors, promValue(float64(io.RxErrors), "interface", io.Interface))
+		txErrors = append(txErrors, promValue(float64(io.TxErrors), "interface", io.Interface))
+	}
+	p.gauge("devhelper_network_receive_bytes_per_second", "Прием по интерфейсу (байт/с)", rx...)
+	p.gauge("devhelper_network_transmit_bytes_per_second", "Передача по интерфейсу (байт/с)", tx...)
+	p.counter("devhelper_network_receive_errors_total", "Ошибки приема с момента загрузки", rxErrors...)
+	p.counter("devhelper_network_transmit_errors_total", "Ошибки передачи с момента загрузки", txErrors...)
+
+	var procCPU, procRSS, procThreads []promSample
+	for _, process := range stats.Processes {
+		labels := []string{"pid", strconv.Itoa(process.PID), "name", process.Name}
+		procCPU = append(procCPU, promValue(process.CPU, labels...))
+		procRSS = append(procRSS, promValue(float64(process.RSS), labels...))
+		procThreads = append(procThreads, promValue(float64(process.Threads), labels...))
+	}
+	p.gauge("devhelper_process_cpu_usage_percent", "Использование CPU процессом (% одного ядра)", procCPU...)
+	p.gauge("devhelper_process_resident_memory_bytes", "Резидентная память процесса (байты)", procRSS...)
+	p.gauge("devhelper_process_threads", "Количество потоков процесса", procThreads...)
+
+	if target := stats.Target; target != nil {
+		p.gauge("devhelper_target_processes", "Количество отслеживаемых процессов", promValue(float64(len(target.PIDs)), "target", target.Name))
+		if len(target.PIDs) > 0 {
+			p.gauge("devhelper_target_cpu_usage_percent", "Использование CPU отслеживаемыми процессами (% одного ядра)", promValue(target.CPU, "target", target.Name))
+			p.gauge("devhelper_target_resident_memory_bytes", "Резидентная память отслеживаемых процессов (байты)", promValue(float64(target.RSS), "target", target.Name))
+			p.gauge("devhelper_target_threads", "Количество потоков отслеживаемых процессов", promValue(float64(target.Threads), "target", target.Name))
+			p.gauge("devhelper_target_open_fds", "Открытые файловые дескрипторы отслеживаемых процессов", promValue(float64(target.FDs), "target", target.Name))
+			p.gauge("devhelper_target_read_bytes_per_second", "Чтение с накопителей отслеживаемыми процессами (байт/с)", promValue(target.ReadRate, "target", target.Name))
+			p.gauge("devhelper_target_write_bytes_per_second", "Запись на накопители отслеживаемыми процессами (байт/с)", promValue(target.WriteRate, "target", target.Name))
+		}
+	}
+
+	if cgroup := stats.Cgroup; cgroup != nil {
+		p.gauge("devhelper_cgroup_cpu_usage_percent", "Использование CPU контейнером (% от лимита)", promValue(cgroup.CPU))
+		if cgroup.CPULimit > 0 {
+			p.gauge("devhelper_cgroup_cpu_limit_cores", "Лимит CPU контейнера (ядра)", promValue(cgroup.CPULimit))
+		}
+		p.gauge("devhelper_cgroup_memory_usage_percent", "Использование памяти контейнером (% от лимита)", promValue(cgroup.Memory))
+		p.gauge("devhelper_cgroup_memory_used_bytes", "Использовано памяти контейнером (байты)", promValue(float64(cgroup.MemoryUsed)))
+		if cgroup.MemoryLimit > 0 {
+			p.gauge("devhelper_cgroup_memory_limit_bytes", "Лимит памяти контейнера (байты)", promValue(float64(cgroup.MemoryLimit)))
+		}
+		p.counter("devhelper_cgroup_cpu_periods_total", "Периоды планировщика CFS контейнера", promValue(float64(cgroup.Periods)))
+		p.counter("devhelper_cgroup_cpu_throttled_periods_total", "Периоды с ограничением CPU контейнера", promValue(float64(cgroup.Throttled)))
+		p.counter("devhelper_cgroup_cpu_throttled_seconds_total", "Время ограничения CPU контейнера (с)", promValue(cgroup.ThrottledTime))
+	}
+
+	firing := make([]promSample, len(alerts))
+	for i, alert := range alerts {
+		v := 0.0
+		if alert.Firing {
+			v = 1
+		}
+		firing[i] = promValue(v, "rule", alert.Rule)
+	}
+	p.gauge("devhelper_alert_firing", "Сработавшие правила оповещений (1 - сработало)", firing...)
+
+	if !stats.Time.IsZero() {
+		p.gauge("devhelper_sample_timestamp_seconds", "Время последнего замера (Unix)", promValue(float64(stats.Time.UnixNano())/1e9))
+	}
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	stats := SystemStats{
+		Time:   time.Unix(1700000000, 0),
+		CPU:    42.5,
+		Cores:  []float64{40, 45},
+		Load1:  0.5,
+		Memory: 50, UsedMem: 4096, TotalMem: 8192,
+		Mounts:    []MountStats{{MountPoint: "/var", Device: "/dev/sda2", FSType: "xfs", Usage: 90, Used: 900, Total: 1000}},
+		DiskIO:    []DiskIOStats{{Device: "sda", ReadBytes: 1024}},
+		NetIO:     []NetIOStats{{Interface: "eth0", RxBytes: 10, RxErrors: 3}},
+		Processes: []ProcessStats{{PID: 42, Name: "go build", CPU: 150}},
+		Target:    &TargetStats{Name: "PID 42", PIDs: []int{42}, FDs: 7},
+		Cgroup:    &CgroupStats{Version: 2, CPU: 80, CPULimit: 1.5, MemoryUsed: 2048, Throttled: 12},
+	}
+	alerts := []alertStatus{{Rule: "cpu > 90", Firing: false}, {Rule: "disk:/var > 85", Firing: true}}
+
+	var buf bytes.Buffer
+	writePrometheus(&buf, stats, alerts)
+	output := buf.String()
+
+	assert.Contains(t, output, "# HELP devhelper_cpu_usage_percent Использование CPU (%)\n# TYPE devhelper_cpu_usage_percent gauge\ndevhelper_cpu_usage_percent 42.5\n")
+	assert.Contains(t, output, `devhelper_cpu_core_usage_percent{core="1"} 45`)
+	assert.Contains(t, output, `devhelper_load_average{period="1m"} 0.5`)
+	assert.Contains(t, output, "devhelper_memory_used_bytes 4096\n")
+	assert.Contains(t, output, `devhelper_filesystem_usage_percent{mountpoint="/var",device="/dev/sda2",fstype="xfs"} 90`)
+	assert.Contains(t, output, `devhelper_disk_read_bytes_per_second{device="sda"} 1024`)
+	assert.Contains(t, output, "# TYPE devhelper_network_receive_errors_total counter\n")
+	assert.Contains(t, output, `devhelper_network_receive_errors_total{interface="eth0"} 3`)
+	assert.Contains(t, output, `devhelper_process_cpu_usage_percent{pid="42",name="go build"} 150`)
+	assert.Contains(t, output, `devhelper_target_open_fds{target="PID 42"} 7`)
+	assert.Contains(t, output, "devhelper_cgroup_cpu_usage_percent 80\n")
+	assert.Contains(t, output, "devhelper_cgroup_cpu_limit_cores 1.5\n")
+	assert.Contains(t, output, "devhelper_cgroup_cpu_throttled_periods_total 12\n")
+	assert.NotContains(t, output, "devhelper_cgroup_memory_limit_bytes")
+	assert.Contains(t, output, `devhelper_alert_firing{rule="cpu > 90"} 0`)
+	assert.Contains(t, output, `devhelper_alert_firing{rule="disk:/var > 85"} 1`)
+	assert.Contains(t, output, "devhelper_sample_timestamp_seconds 1.7e+09\n")
+
+	// Метрики без значений не выводятся
+	buf.Reset()
+	writePrometheus(&buf, SystemStats{}, nil)
+	assert.NotContains(t, buf.String(), "devhelper_filesystem_usage_percent")
+	assert.NotContains(t, buf.String(), "devhelper_target_")
+	assert.NotContains(t, buf.String(), "devhelper_cgroup_")
+	assert.NotContains(t, buf.String(), "devhelper_alert_firing")
+}
+
+func TestFormatPromLabels(t *testing.T) {
+	assert.Equal(t, "", formatPromLabels(nil))
+	assert.Equal(t, `{a="1",b="2"}`, formatPromLabels([]string{"a", "1", "b", "2"}))
+	assert.Equal(t, `{rule="a \"b\" \\ c\nd"}`, formatPromLabels([]string{"rule", "a \"b\" \\ c\nd"}))
+}
+
+func TestServeMux(t *testing.T) {
+	store := &statsStore{}
+	handler := newServeMux(store)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// До первого замера
+	assert.Equal(t, http.StatusServiceUnavailable, get("/metrics").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/stats").Code)
+
+	store.set(SystemStats{CPU: 12.5, Memory: 30}, nil)
+
+	rec := get("/metrics")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Contains(t, rec.Body.String(), "devhelper_cpu_usage_percent 12.5")
+
+	rec = get("/stats")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var stats SystemStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 30.0, stats.Memory)
+
+	assert.Equal(t, http.StatusOK, get("/").Code)
+	assert.Equal(t, http.StatusNotFound, get("/unknown").Code)
+}
+
+func TestMonitor_Serve(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Сбор метрик поддерживается только на Linux")
+	}
+
+	rule, err := ParseAlertRule("mem >= 0")
+	require.NoError(t, err)
+	monitor := NewMonitor(10*time.Millisecond, displayServe, Options{Alerts: []AlertRule{rule}, UntilAlert: true})
+	var buf bytes.Buffer
+	monitor.writer = &buf
+	monitor.alerts.stderr = &buf
+
+	// Сервер останавливается после срабатывания правила в первом замере
+	assert.ErrorIs(t, monitor.Serve("127.0.0.1:0"), ErrAlertTriggered)
+	assert.Contains(t, buf.String(), "Метрики доступны по адресу http://127.0.0.1:")
+
+	assert.Error(t, NewMonitor(time.Second, displayServe, Options{}).Serve("127.0.0.1:-1"))
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// displayRecord режим вывода замеров в формате JSON Lines для monitor record
+const displayRecord = "record"
+
+// writeSample записывает замер одной строкой JSON
+func (m *Monitor) writeSample(stats SystemStats) error {
+	if err := json.NewEncoder(m.writer).Encode(stats); err != nil {
+		return fmt.Errorf("не удалось записать замер: %w", err)
+	}
+	return nil
+}
+
+// sessionReader последовательно читает замеры записанной сессии
+type sessionReader struct {
+	decoder *json.Decoder
+	count   int
+}
+
+// newSessionReader создает читатель сессии в формате JSON Lines
+func newSessionReader(r io.Reader) *sessionReader {
+	return &sessionReader{decoder: json.NewDecoder(r)}
+}
+
+// Next возвращает следующий замер или io.EOF, если замеры закончились
+func (s *sessionReader) Next() (SystemStats, error) {
+	var stats SystemStats
+	if err := s.decoder.Decode(&stats); err != nil {
+		if errors.Is(err, io.EOF) {
+			return stats, io.EOF
+		}
+		return stats, fmt.Errorf("неверный формат замера %d: %w", s.count+1, err)
+	}
+	s.count++
+	return stats, nil
+}
+
+// ReadSession читает все замеры записанной сессии
+func ReadSession(r io.Reader) ([]SystemStats, error) {
+	reader := newSessionReader(r)
+
+	var samples []SystemStats
+	for {
+		stats, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, stats)
+	}
+}
+
+// Replay воспроизводит записанную сессию в выбранном режиме отображения.
+// Паузы между замерами равны записанным, деленным на speed; при speed 0 замеры выводятся без пауз.
+// Воспроизведение завершается после count замеров или по истечении duration, как и мониторинг.
+func (m *Monitor) Replay(r io.Reader, speed float64) error {
+	m.watchSignals()
+
+	if m.displayMode == "dashboard" {
+		fmt.Fprint(m.writer, "\033[?25l")       // Скрываем курсор
+		defer fmt.Fprint(m.writer, "\033[?25h") // Восстанавливаем курсор при выходе
+	}
+
+	var deadline <-chan time.Time
+	if m.duration > 0 {
+		timer := time.NewTimer(m.duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	reader := newSessionReader(r)
+	var prev time.Time
+	for samples := 0; m.count == 0 || samples < m.count; samples++ {
+		stats, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !prev.IsZero() && speed > 0 {
+			if delay := time.Duration(float64(stats.Time.Sub(prev)) / speed); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-m.ctx.Done():
+					timer.Stop()
+					return nil
+				case <-deadline:
+					timer.Stop()
+					return nil
+				case <-timer.C:
+				}
+			}
+		}
+		if m.ctx.Err() != nil {
+			return nil
+		}
+		// Без пауз (speed 0 или одинаковое время замеров) длительность проверяется перед каждым замером
+		select {
+		case <-deadline:
+			return nil
+		default:
+		}
+		prev = stats.Time
+
+		if err := m.handleStats(stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
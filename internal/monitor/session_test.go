@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession возвращает записанную сессию из трех замеров с интервалом в секунду
+func testSession(t *testing.T) string {
+	t.Helper()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	monitor := &Monitor{displayMode: displayRecord, writer: &buf}
+	for i, cpu := range []float64{10, 95, 50} {
+		stats := SystemStats{
+			Time:   start.Add(time.Duration(i) * time.Second),
+			CPU:    cpu,
+			Cores:  []float64{cpu},
+			Memory: 40,
+			Mounts: []MountStats{{MountPoint: "/var", Usage: 70}},
+			DiskIO: []DiskIOStats{{Device: "sda", ReadBytes: float64(i) * 1024}},
+			Target: &TargetStats{Name: "PID 1", PIDs: []int{1}, RSS: 1024},
+		}
+		require.NoError(t, monitor.handleStats(stats))
+	}
+	return buf.String()
+}
+
+func TestRecordAndReadSession(t *testing.T) {
+	session := testSession(t)
+	assert.Equal(t, 3, strings.Count(session, "\n"))
+	assert.Contains(t, session, `"time":"2024-01-01T12:00:01Z"`)
+	assert.Contains(t, session, `"mount_point":"/var"`)
+
+	samples, err := ReadSession(strings.NewReader(session))
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.Equal(t, 95.0, samples[1].CPU)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 2, 0, time.UTC), samples[2].Time.UTC())
+	assert.Equal(t, "/var", samples[2].Mounts[0].MountPoint)
+	require.NotNil(t, samples[2].Target)
+	assert.Equal(t, []int{1}, samples[2].Target.PIDs)
+
+	_, err = ReadSession(strings.NewReader(session + "{broken\n"))
+	assert.ErrorContains(t, err, "замера 4")
+
+	samples, err = ReadSession(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+}
+
+func TestMonitor_Replay(t *testing.T) {
+	session := testSession(t)
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor := &Monitor{displayMode: "simple", ctx: ctx, cancelFunc: cancel, writer: &buf, thresholds: DefaultThresholds}
+
+	require.NoError(t, monitor.Replay(strings.NewReader(session), 0))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+	assert.True(t, strings.HasPrefix(lines[0], "12:00:00 | CPU: 10.0%"))
+	assert.True(t, strings.HasPrefix(lines[2], "12:00:01 | CPU: 95.0%"))
+
+	// Паузы между замерами делятся на скорость
+	buf.Reset()
+	started := time.Now()
+	require.NoError(t, monitor.Replay(strings.NewReader(session), 20))
+	assert.GreaterOrEqual(t, time.Since(started), 100*time.Millisecond)
+
+	_, err := ReadSession(strings.NewReader("not json"))
+	require.Error(t, err)
+	assert.Error(t, monitor.Replay(strings.NewReader("not json"), 0))
+}
+
+func TestMonitor_ReplayUntilAlert(t *testing.T) {
+	rule, err := ParseAlertRule("cpu > 90")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	monitor := NewMonitor(time.Second, "simple", Options{Alerts: []AlertRule{rule}, UntilAlert: true})
+	monitor.writer = &buf
+	monitor.alerts.stderr = &buf
+
+	err = monitor.Replay(strings.NewReader(testSession(t)), 0)
+	assert.ErrorIs(t, err, ErrAlertTriggered)
+	assert.Contains(t, buf.String(), "2024-01-01 12:00:01 [FIRING] cpu > 90: 95.00")
+	assert.NotContains(t, buf.String(), "12:00:02 |")
+}
+
+// slowWriter задерживает каждую запись, имитируя медленный терминал
+type slowWriter struct {
+	w io.Writer
+}
+
+func (s slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(20 * time.Millisecond)
+	return s.w.Write(p)
+}
+
+func TestMonitor_ReplayBounded(t *testing.T) {
+	session := testSession(t)
+
+	tests := []struct {
+		name  string
+		opts  Options
+		speed float64
+		slow  bool
+		want  []string
+	}{
+		{name: "Количество замеров", opts: Options{Count: 2}, want: []string{"12:00:00 |", "12:00:01 |"}},
+		{name: "Один замер", opts: Options{Count: 1}, want: []string{"12:00:00 |"}},
+		// Пауза в 1 секунду между замерами не укладывается в длительность
+		{name: "Длительность", opts: Options{Duration: 50 * time.Millisecond}, speed: 1, want: []string{"12:00:00 |"}},
+		// Без пауз длительность истекает за время вывода первого замера
+		{name: "Длительность без пауз", opts: Options{Duration: 10 * time.Millisecond}, speed: 0, slow: true, want: []string{"12:00:00 |"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			monitor := NewMonitor(time.Second, "simple", tt.opts)
+			monitor.writer = &buf
+			if tt.slow {
+				monitor.writer = slowWriter{&buf}
+			}
+
+			require.NoError(t, monitor.Replay(strings.NewReader(session), tt.speed))
+			var times []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if strings.HasPrefix(line, "12:") {
+					times = append(times, line[:10])
+				}
+			}
+			assert.Equal(t, tt.want, times)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package monitor
+
+import "syscall"
+
+// statFS возвращает информацию о файловой системе, содержащей path
+func statFS(path string) (fsUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return fsUsage{}, err
+	}
+
+	bsize := uint64(st.Bsize)
+	return fsUsage{
+		Total:     st.Blocks * bsize,
+		Free:      st.Bfree * bsize,
+		Avail:     st.Bavail * bsize,
+		Files:     st.Files,
+		FilesFree: st.Ffree,
+	}, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package monitor
+
+import (
+	"errors"
+	"runtime"
+)
+
+// statFS не поддерживается на платформах, отличных от Linux
+func statFS(path string) (fsUsage, error) {
+	return fsUsage{}, errors.New("сбор статистики диска не поддерживается на " + runtime.GOOS)
+}
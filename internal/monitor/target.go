@@ -0,0 +1,222 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TargetOptions определяет отслеживаемый процесс
+type TargetOptions struct {
+	PID      int    // PID отслеживаемого процесса
+	Command  string // Имя отслеживаемых процессов (все процессы с таким именем)
+	Children bool   // Учитывать дочерние процессы
+}
+
+// Enabled сообщает, задан ли отслеживаемый процесс
+func (o TargetOptions) Enabled() bool {
+	return o.PID > 0 || o.Command != ""
+}
+
+// Description возвращает описание отслеживаемого процесса для вывода
+func (o TargetOptions) Description() string {
+	desc := o.Command
+	if o.PID > 0 {
+		desc = fmt.Sprintf("PID %d", o.PID)
+	}
+	if o.Children {
+		desc += " + children"
+	}
+	return desc
+}
+
+// TargetStats представляет суммарную статистику отслеживаемых процессов
+type TargetStats struct {
+	Name       string  `json:"name"`        // Описание цели (PID или имя)
+	PIDs       []int   `json:"pids"`        // PID учтенных процессов, пустой если процесс не найден
+	CPU        float64 `json:"cpu"`         // Использование CPU (% одного ядра)
+	RSS        uint64  `json:"rss"`         // Резидентная память (байты)
+	Memory     float64 `json:"memory"`      // Доля резидентной памяти от общей (%)
+	Threads    int     `json:"threads"`     // Количество потоков
+	FDs        int     `json:"fds"`         // Открытые файловые дескрипторы
+	ReadBytes  uint64  `json:"read_bytes"`  // Прочитано с накопителей с момента запуска (байты)
+	WriteBytes uint64  `json:"write_bytes"` // Записано на накопители с момента запуска (байты)
+	ReadRate   float64 `json:"read_rate"`   // Чтение с накопителей (байт/с)
+	WriteRate  float64 `json:"write_rate"`  // Запись на накопители (байт/с)
+}
+
+// processIO содержит счетчики ввода-вывода процесса из /proc/[pid]/io
+type processIO struct {
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// collectTarget собирает статистику отслеживаемого процесса и, при необходимости, его потомков.
+// Нагрузка CPU и скорость ввода-вывода учитываются только для процессов,
+// присутствовавших и в предыдущем замере.
+func (c *Collector) collectTarget(infos []processInfo, coreTicks, elapsed float64, memTotal uint64) *TargetStats {
+	target := &TargetStats{Name: c.target.Description()}
+
+	selected := selectTargetProcesses(c.procRoot, infos, c.target)
+
+	prevTicks, prevIO := c.prevTargetTicks, c.prevTargetIO
+	c.prevTargetTicks = make(map[int]uint64, len(selected))
+	c.prevTargetIO = make(map[int]processIO, len(selected))
+
+	var ticks uint64
+	var read, write uint64
+	for _, info := range selected {
+		dir := filepath.Join(c.procRoot, strconv.Itoa(info.pid))
+
+		target.PIDs = append(target.PIDs, info.pid)
+		target.RSS += info.rss
+		target.Threads += info.threads
+		target.FDs += countFDs(dir)
+
+		if prev, ok := prevTicks[info.pid]; ok && info.ticks >= prev {
+			ticks += info.ticks - prev
+		}
+		c.prevTargetTicks[info.pid] = info.ticks
+
+		// Счетчики ввода-вывода чужих процессов недоступны без прав, такие процессы не учитываются
+		io, err := readProcessIO(filepath.Join(dir, "io"))
+		if err != nil {
+			continue
+		}
+		target.ReadBytes += io.readBytes
+		target.WriteBytes += io.writeBytes
+		if prev, ok := prevIO[info.pid]; ok {
+			if io.readBytes >= prev.readBytes {
+				read += io.readBytes - prev.readBytes
+			}
+			if io.writeBytes >= prev.writeBytes {
+				write += io.writeBytes - prev.writeBytes
+			}
+		}
+		c.prevTargetIO[info.pid] = io
+	}
+
+	target.Memory = percentOf(target.RSS, memTotal)
+	if coreTicks > 0 {
+		target.CPU = float64(ticks) / coreTicks * 100
+	}
+	if elapsed > 0 {
+		target.ReadRate = float64(read) / elapsed
+		target.WriteRate = float64(write) / elapsed
+	}
+	sort.Ints(target.PIDs)
+
+	return target
+}
+
+// selectTargetProcesses выбирает процессы по PID или имени и, если нужно, их потомков.
+// Полные имена процессов при необходимости читаются из procfs с корнем procRoot.
+func selectTargetProcesses(procRoot string, infos []processInfo, opts TargetOptions) []processInfo {
+	selected := make(map[int]bool)
+	for _, info := range infos {
+		if (opts.PID > 0 && info.pid == opts.PID) || (opts.Command != "" && matchCommand(procRoot, info, opts.Command)) {
+			selected[info.pid] = true
+		}
+	}
+
+	if opts.Children {
+		children := make(map[int][]int)
+		for _, info := range infos {
+			children[info.ppid] = append(children[info.ppid], info.pid)
+		}
+
+		queue := make([]int, 0, len(selected))
+		for pid := range selected {
+			queue = append(queue, pid)
+		}
+		for len(queue) > 0 {
+			pid := queue[0]
+			queue = queue[1:]
+			for _, child := range children[pid] {
+				if !selected[child] {
+					selected[child] = true
+					queue = append(queue, child)
+				}
+			}
+		}
+	}
+
+	var result []processInfo
+	for _, info := range infos {
+		if selected[info.pid] {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// commNameLen длина, до которой ядро обрезает имя процесса в /proc/[pid]/stat и status
+const commNameLen = 15
+
+// matchCommand сообщает, совпадает ли имя процесса с command. Имя длиной commNameLen
+// может быть обрезано ядром, поэтому для него сравнивается имя исполняемого файла
+// из /proc/[pid]/cmdline, а если его не удалось прочитать - из ссылки exe.
+func matchCommand(procRoot string, info processInfo, command string) bool {
+	if info.name == command {
+		return true
+	}
+	if len(info.name) != commNameLen || !strings.HasPrefix(command, info.name) {
+		return false
+	}
+
+	dir := filepath.Join(procRoot, strconv.Itoa(info.pid))
+	if data, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		argv0, _, _ := strings.Cut(string(data), "\x00")
+		if argv0 != "" {
+			return filepath.Base(argv0) == command
+		}
+	}
+	// У потоков ядра и завершающихся процессов cmdline пуст
+	if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+		return filepath.Base(strings.TrimSuffix(exe, " (deleted)")) == command
+	}
+	return false
+}
+
+// countFDs возвращает количество открытых файловых дескрипторов процесса
+func countFDs(dir string) int {
+	entries, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
+
+// readProcessIO читает счетчики ввода-вывода процесса из /proc/[pid]/io
+func readProcessIO(path string) (processIO, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return processIO{}, err
+	}
+	defer file.Close()
+
+	var io processIO
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "read_bytes":
+			io.readBytes = n
+		case "write_bytes":
+			io.writeBytes = n
+		}
+	}
+
+	return io, scanner.Err()
+}
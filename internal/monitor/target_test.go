@@ -0,0 +1,163 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestProcessIO создает файл io и каталог fd процесса в тестовом дереве procfs
+func writeTestProcessIO(t *testing.T, root string, pid int, readBytes, writeBytes uint64, fds int) {
+	t.Helper()
+	writeProcFile(t, root, fmt.Sprintf("%d/io", pid),
+		fmt.Sprintf("rchar: 1000\nwchar: 2000\nsyscr: 10\nsyscw: 20\nread_bytes: %d\nwrite_bytes: %d\ncancelled_write_bytes: 0\n",
+			readBytes, writeBytes))
+	fdDir := filepath.Join(root, fmt.Sprintf("%d/fd", pid))
+	require.NoError(t, os.MkdirAll(fdDir, 0755))
+	for i := 0; i < fds; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(fdDir, fmt.Sprintf("%d", i)), nil, 0644))
+	}
+}
+
+func TestTargetOptions(t *testing.T) {
+	assert.False(t, TargetOptions{}.Enabled())
+	assert.True(t, TargetOptions{PID: 42}.Enabled())
+	assert.True(t, TargetOptions{Command: "nginx"}.Enabled())
+
+	assert.Equal(t, "PID 42", TargetOptions{PID: 42}.Description())
+	assert.Equal(t, "nginx + children", TargetOptions{Command: "nginx", Children: true}.Description())
+}
+
+func TestSelectTargetProcesses(t *testing.T) {
+	infos := []processInfo{
+		{pid: 1, ppid: 0, name: "init"},
+		{pid: 10, ppid: 1, name: "nginx"},
+		{pid: 11, ppid: 10, name: "worker"},
+		{pid: 12, ppid: 11, name: "helper"},
+		{pid: 20, ppid: 1, name: "nginx"},
+		{pid: 30, ppid: 1, name: "sshd"},
+	}
+
+	tests := []struct {
+		name string
+		opts TargetOptions
+		want []int
+	}{
+		{"по PID", TargetOptions{PID: 10}, []int{10}},
+		{"по PID с потомками", TargetOptions{PID: 10, Children: true}, []int{10, 11, 12}},
+		{"по имени", TargetOptions{Command: "nginx"}, []int{10, 20}},
+		{"по имени с потомками", TargetOptions{Command: "nginx", Children: true}, []int{10, 11, 12, 20}},
+		{"не найден", TargetOptions{PID: 99, Children: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pids []int
+			for _, info := range selectTargetProcesses(t.TempDir(), infos, tt.opts) {
+				pids = append(pids, info.pid)
+			}
+			assert.Equal(t, tt.want, pids)
+		})
+	}
+}
+
+func TestSelectTargetProcesses_LongName(t *testing.T) {
+	root := t.TempDir()
+	// Ядро обрезает имена процессов до 15 символов
+	writeTestProcess(t, root, 10, 1, "prometheus-node", 0, 1024, 1)
+	writeProcFile(t, root, "10/cmdline", "/usr/bin/prometheus-node-exporter\x00--web.listen-address=:9100\x00")
+	writeTestProcess(t, root, 11, 1, "prometheus-node", 0, 1024, 1)
+	writeProcFile(t, root, "11/cmdline", "./prometheus-node-agent\x00")
+	writeTestProcess(t, root, 12, 1, "prometheus-node", 0, 1024, 1)
+	writeProcFile(t, root, "12/cmdline", "")
+	require.NoError(t, os.Symlink("/opt/bin/prometheus-node-exporter", filepath.Join(root, "12/exe")))
+
+	collector := NewCollector(Options{ProcFS: root})
+	infos, err := collector.readProcesses()
+	require.NoError(t, err)
+
+	tests := []struct {
+		command string
+		want    []int
+	}{
+		{"prometheus-node-exporter", []int{10, 12}},
+		{"prometheus-node-agent", []int{11}},
+		// Обрезанное имя совпадает со всеми процессами
+		{"prometheus-node", []int{10, 11, 12}},
+		{"prometheus-node-exp", nil},
+	}
+	for _, tt := range tests {
+		var pids []int
+		for _, info := range selectTargetProcesses(root, infos, TargetOptions{Command: tt.command}) {
+			pids = append(pids, info.pid)
+		}
+		assert.Equal(t, tt.want, pids, tt.command)
+	}
+}
+
+func TestCollector_CollectTarget(t *testing.T) {
+	root := t.TempDir()
+	writeTestProcess(t, root, 1, 0, "init", 100, 4096, 1)
+	writeTestProcess(t, root, 200, 1, "postgres", 1000, 512000, 8)
+	writeTestProcess(t, root, 201, 200, "postgres", 300, 102400, 2)
+	writeTestProcessIO(t, root, 200, 4096, 8192, 5)
+	writeTestProcessIO(t, root, 201, 0, 1024, 3)
+
+	collector := NewCollector(Options{ProcFS: root, Target: TargetOptions{PID: 200, Children: true}})
+	memTotal := uint64(1024 * 1024 * 1024)
+
+	infos, err := collector.readProcesses()
+	require.NoError(t, err)
+	target := collector.collectTarget(infos, 0, 0, memTotal)
+	assert.Equal(t, "PID 200 + children", target.Name)
+	assert.Equal(t, []int{200, 201}, target.PIDs)
+	assert.Equal(t, uint64(614400*1024), target.RSS)
+	assert.InDelta(t, 58.6, target.Memory, 0.1)
+	assert.Equal(t, 10, target.Threads)
+	assert.Equal(t, 8, target.FDs)
+	assert.Equal(t, uint64(4096), target.ReadBytes)
+	assert.Equal(t, uint64(9216), target.WriteBytes)
+	assert.Equal(t, 0.0, target.CPU)
+	assert.Equal(t, 0.0, target.ReadRate)
+
+	// Второй замер через 2 секунды: 200 тиков одного ядра
+	writeTestProcess(t, root, 200, 1, "postgres", 1100, 512000, 8)
+	writeTestProcess(t, root, 201, 200, "postgres", 350, 102400, 2)
+	writeTestProcessIO(t, root, 200, 4096+2048, 8192, 5)
+	writeTestProcessIO(t, root, 201, 0, 1024+4096, 3)
+
+	infos, err = collector.readProcesses()
+	require.NoError(t, err)
+	target = collector.collectTarget(infos, 200, 2, memTotal)
+	assert.InDelta(t, 75.0, target.CPU, 0.001)
+	assert.InDelta(t, 1024.0, target.ReadRate, 0.001)
+	assert.InDelta(t, 2048.0, target.WriteRate, 0.001)
+
+	// Процесс завершился
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "200")))
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "201")))
+	infos, err = collector.readProcesses()
+	require.NoError(t, err)
+	target = collector.collectTarget(infos, 200, 2, memTotal)
+	assert.Empty(t, target.PIDs)
+	assert.Equal(t, 0.0, target.CPU)
+}
+
+func TestReadProcessIO(t *testing.T) {
+	root := t.TempDir()
+	writeTestProcessIO(t, root, 1, 123, 456, 0)
+
+	io, err := readProcessIO(filepath.Join(root, "1/io"))
+	require.NoError(t, err)
+	assert.Equal(t, processIO{readBytes: 123, writeBytes: 456}, io)
+
+	_, err = readProcessIO(filepath.Join(root, "2/io"))
+	assert.Error(t, err)
+
+	assert.Equal(t, 0, countFDs(filepath.Join(root, "1")))
+	assert.Equal(t, 0, countFDs(filepath.Join(root, "2")))
+}
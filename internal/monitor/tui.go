@@ -0,0 +1,241 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Панели интерактивного режима
+const (
+	panelAll       = iota // Все разделы
+	panelSystem           // CPU, память, диски и контейнер
+	panelIO               // Дисковый и сетевой ввод-вывод
+	panelProcesses        // Процессы и отслеживаемый процесс
+)
+
+// panelNames названия панелей для строки состояния
+var panelNames = []string{"все", "система", "ввод-вывод", "процессы"}
+
+// sortOrder порядок переключения сортировки процессов клавишей s
+var sortOrder = []string{SortByCPU, SortByMem, SortByPID}
+
+// Клавиши интерактивного режима
+const (
+	keyQuit     = "quit"
+	keyNext     = "next"
+	keyPrev     = "prev"
+	keySort     = "sort"
+	keyPause    = "pause"
+	keyIncrease = "+"
+	keyDecrease = "-"
+)
+
+// intervalStep шаг изменения интервала клавишами + и -
+const intervalStep = time.Second
+
+// terminalUI переводит терминал в интерактивный режим и читает нажатия клавиш
+type terminalUI struct {
+	fd        int
+	state     *term.State
+	keys      chan string
+	resize    chan os.Signal
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// startTerminalUI переводит терминал в raw-режим, переключается на альтернативный
+// экран и запускает чтение клавиш из stdin
+func startTerminalUI() (*terminalUI, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось перевести терминал в интерактивный режим: %w", err)
+	}
+
+	ui := &terminalUI{
+		fd:     fd,
+		state:  state,
+		keys:   make(chan string),
+		resize: make(chan os.Signal, 1),
+		done:   make(chan struct{}),
+	}
+	notifyResize(ui.resize)
+	fmt.Fprint(os.Stdout, "\033[?1049h") // Альтернативный экран
+
+	go ui.readKeys(os.Stdin)
+	return ui, nil
+}
+
+// close восстанавливает исходное состояние терминала; безопасен при повторном вызове
+func (ui *terminalUI) close() {
+	ui.closeOnce.Do(func() {
+		close(ui.done)
+		signal.Stop(ui.resize)
+		fmt.Fprint(os.Stdout, "\033[?1049l") // Возврат к основному экрану
+		term.Restore(ui.fd, ui.state)
+	})
+}
+
+// readKeys читает нажатия клавиш до ошибки чтения или закрытия интерфейса
+func (ui *terminalUI) readKeys(r io.Reader) {
+	buf := make([]byte, 32)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			select {
+			case ui.keys <- key:
+			case <-ui.done:
+				return
+			}
+		}
+	}
+}
+
+// parseKeys преобразует прочитанные из терминала байты в клавиши интерактивного режима.
+// Цифры 0-3 возвращаются как есть и выбирают панель, неизвестные клавиши и escape-последовательности
+// (функциональные клавиши, Alt+клавиша) пропускаются.
+func parseKeys(data []byte) []string {
+	var keys []string
+	for i := 0; i < len(data); i++ {
+		switch b := data[i]; b {
+		case 'q', 'Q', 0x03: // 0x03 - Ctrl+C в raw-режиме
+			keys = append(keys, keyQuit)
+		case '\t':
+			keys = append(keys, keyNext)
+		case 's', 'S':
+			keys = append(keys, keySort)
+		case 'p', 'P', ' ':
+			keys = append(keys, keyPause)
+		case '+', '=':
+			keys = append(keys, keyIncrease)
+		case '-', '_':
+			keys = append(keys, keyDecrease)
+		case '0', '1', '2', '3':
+			keys = append(keys, string(b))
+		case 0x1b:
+			if i+1 == len(data) {
+				keys = append(keys, keyQuit) // Отдельный Esc, за которым ничего не прочитано
+				continue
+			}
+			var final byte
+			final, i = escapeSequence(data, i)
+			// Стрелки и Shift+Tab: ESC [ C, ESC [ D, ESC [ Z (ESC O C, ESC O D в режиме приложения)
+			switch final {
+			case 'C':
+				keys = append(keys, keyNext)
+			case 'D', 'Z':
+				keys = append(keys, keyPrev)
+			}
+		}
+	}
+	return keys
+}
+
+// escapeSequence разбирает escape-последовательность, начинающуюся с ESC в data[start].
+// Возвращает завершающий байт последовательности CSI (ESC [ ...) или SS3 (ESC O x) и индекс
+// ее последнего байта. Для ESC с другим символом (Alt+клавиша) и незавершенных
+// последовательностей завершающий байт равен 0, они пропускаются целиком.
+func escapeSequence(data []byte, start int) (byte, int) {
+	i := start + 1
+	switch data[i] {
+	case '[':
+		// Параметры и промежуточные байты 0x20-0x3f, завершающий байт 0x40-0x7e
+		for i++; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				return data[i], i
+			}
+			if data[i] < 0x20 || data[i] > 0x3f {
+				return 0, i - 1
+			}
+		}
+		return 0, len(data) - 1
+	case 'O':
+		if i+1 < len(data) {
+			return data[i+1], i + 1
+		}
+		return 0, i
+	}
+	return 0, i
+}
+
+// handleKey применяет нажатую клавишу к состоянию панели.
+// Возвращает true, если нужно завершить мониторинг.
+func (m *Monitor) handleKey(key string) bool {
+	switch key {
+	case keyQuit:
+		return true
+	case keyNext:
+		m.panel = (m.panel + 1) % len(panelNames)
+	case keyPrev:
+		m.panel = (m.panel + len(panelNames) - 1) % len(panelNames)
+	case "0", "1", "2", "3":
+		m.panel = int(key[0] - '0')
+	case keySort:
+		next := sortOrder[0]
+		for i, sortBy := range sortOrder {
+			if sortBy == m.collector.processes.SortBy {
+				next = sortOrder[(i+1)%len(sortOrder)]
+			}
+		}
+		m.collector.processes.SortBy = next
+		// Уже показанные процессы пересортировываются сразу, не дожидаясь замера
+		if m.last != nil {
+			sortProcesses(m.last.Processes, next)
+		}
+	case keyPause:
+		m.paused = !m.paused
+	case keyIncrease:
+		m.interval += intervalStep
+	case keyDecrease:
+		if m.interval > intervalStep {
+			m.interval -= intervalStep
+		}
+	}
+	return false
+}
+
+// showPanel проверяет, отображается ли раздел на текущей панели
+func (m *Monitor) showPanel(panel int) bool {
+	return m.panel == panelAll || m.panel == panel
+}
+
+// redraw перерисовывает панель по последнему замеру
+func (m *Monitor) redraw() {
+	if m.last != nil {
+		m.displayDashboard(*m.last)
+	}
+}
+
+// statusLine возвращает строку подсказки по клавишам интерактивного режима
+func (m *Monitor) statusLine() string {
+	status := fmt.Sprintf("[Tab/0-3] панель: %s | [s] сортировка: %s | [p] пауза | [+/-] интервал: %s | [q] выход",
+		panelNames[m.panel], m.collector.processes.SortBy, m.interval)
+	if m.paused {
+		status = "ПАУЗА | " + status
+	}
+	return status
+}
+
+// crlfWriter заменяет переводы строк на \r\n: в raw-режиме терминал
+// не возвращает каретку в начало строки
+type crlfWriter struct {
+	w io.Writer
+}
+
+// Write записывает данные с заменой \n на \r\n
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
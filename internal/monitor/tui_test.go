@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"Выход", "q", []string{keyQuit}},
+		{"Ctrl+C", "\x03", []string{keyQuit}},
+		{"Отдельный Esc", "\x1b", []string{keyQuit}},
+		{"Переключение панелей", "\t\x1b[Z\x1b[C\x1b[D", []string{keyNext, keyPrev, keyNext, keyPrev}},
+		{"Выбор панели", "2", []string{"2"}},
+		{"Несколько клавиш за одно чтение", "s p+-", []string{keySort, keyPause, keyPause, keyIncrease, keyDecrease}},
+		{"Неизвестные клавиши", "xyz\x1b[A", nil},
+		{"Стрелки в режиме приложения", "\x1bOC\x1bOD", []string{keyNext, keyPrev}},
+		// F1 (SS3), F5 и Ctrl+Up (CSI с параметрами) и Alt+q не завершают мониторинг
+		{"Функциональные клавиши", "\x1bOP\x1b[15~\x1b[1;5A\x1bq", nil},
+		{"Клавиша после последовательности", "\x1b[15~2", []string{"2"}},
+		{"Незавершенная последовательность", "\x1b[1;", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseKeys([]byte(tt.input)))
+		})
+	}
+}
+
+func TestMonitor_HandleKey(t *testing.T) {
+	monitor := NewMonitor(time.Second, "dashboard", Options{Processes: ProcessOptions{SortBy: SortByCPU}})
+	monitor.last = &SystemStats{Processes: []ProcessStats{
+		{PID: 2, CPU: 50, RSS: 100},
+		{PID: 1, CPU: 10, RSS: 200},
+	}}
+
+	// Панели переключаются по кругу
+	assert.False(t, monitor.handleKey(keyPrev))
+	assert.Equal(t, panelProcesses, monitor.panel)
+	monitor.handleKey(keyNext)
+	assert.Equal(t, panelAll, monitor.panel)
+	monitor.handleKey("2")
+	assert.Equal(t, panelIO, monitor.panel)
+
+	// Сортировка меняется для следующих замеров и сразу для показанных процессов
+	monitor.handleKey(keySort)
+	assert.Equal(t, SortByMem, monitor.collector.processes.SortBy)
+	assert.Equal(t, 1, monitor.last.Processes[0].PID)
+	monitor.handleKey(keySort)
+	monitor.handleKey(keySort)
+	assert.Equal(t, SortByCPU, monitor.collector.processes.SortBy)
+
+	monitor.handleKey(keyPause)
+	assert.True(t, monitor.paused)
+	monitor.handleKey(keyPause)
+	assert.False(t, monitor.paused)
+
+	// Интервал не уменьшается меньше шага
+	monitor.handleKey(keyIncrease)
+	assert.Equal(t, 2*time.Second, monitor.interval)
+	monitor.handleKey(keyDecrease)
+	monitor.handleKey(keyDecrease)
+	assert.Equal(t, time.Second, monitor.interval)
+
+	assert.True(t, monitor.handleKey(keyQuit))
+}
+
+func TestDisplayDashboard_Panels(t *testing.T) {
+	stats := SystemStats{
+		CPU:       50,
+		DiskIO:    []DiskIOStats{{Device: "sda"}},
+		Processes: []ProcessStats{{PID: 4242, Name: "postgres"}},
+	}
+
+	var buf bytes.Buffer
+	monitor := &Monitor{
+		interval:   time.Second,
+		ctx:        context.Background(),
+		cancelFunc: func() {},
+		writer:     &buf,
+		collector:  NewCollector(Options{Processes: ProcessOptions{SortBy: SortByMem}}),
+		ui:         &terminalUI{},
+		panel:      panelIO,
+		paused:     true,
+	}
+	monitor.displayDashboard(stats)
+
+	output := buf.String()
+	assert.Contains(t, output, "Disk I/O")
+	assert.NotContains(t, output, "Memory")
+	assert.NotContains(t, output, "postgres")
+	assert.Contains(t, output, "ПАУЗА | [Tab/0-3] панель: ввод-вывод | [s] сортировка: mem")
+	assert.NotContains(t, output, "Ctrl+C")
+}
+
+func TestCRLFWriter(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := crlfWriter{w: &buf}.Write([]byte("a\nb\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "a\r\nb\r\n", buf.String())
+}
@@ -54,7 +54,17 @@ type Config struct {
 		DefaultDisplay  string `json:"default_display" yaml:"default_display"`
 		LogToFile       bool   `json:"log_to_file" yaml:"log_to_file"`
 		LogFilePath     string `json:"log_file_path" yaml:"log_file_path"`
+
+		// Ротация журнала замеров: по размеру (МБ) и по времени, хранение старых файлов
+		LogMaxSizeMB   int           `json:"log_max_size_mb" yaml:"log_max_size_mb"`
+		LogRotateEvery time.Duration `json:"log_rotate_every" yaml:"log_rotate_every"`
+		LogMaxBackups  int           `json:"log_max_backups" yaml:"log_max_backups"`
+		LogMaxAge      time.Duration `json:"log_max_age" yaml:"log_max_age"`
 	} `json:"monitor" yaml:"monitor"`
+
+	// Профиль, применяемый по умолчанию, и именованные профили
+	ActiveProfile string             `json:"active_profile,omitempty" yaml:"active_profile,omitempty"`
+	Profiles      map[string]Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
 }
 
 // defaultConfig возвращает конфигурацию по умолчанию
@@ -93,29 +103,64 @@ func defaultConfig() *Config {
 	config.Monitor.DefaultDisplay = "dashboard"
 	config.Monitor.LogToFile = false
 	config.Monitor.LogFilePath = ""
+	config.Monitor.LogMaxSizeMB = 10
+	config.Monitor.LogRotateEvery = 24 * time.Hour
+	config.Monitor.LogMaxBackups = 5
+	config.Monitor.LogMaxAge = 7 * 24 * time.Hour
 
 	return &config
 }
 
+// Default возвращает конфигурацию по умолчанию
+func Default() *Config {
+	return defaultConfig()
+}
+
 // ConfigManager управляет конфигурацией приложения
 type ConfigManager struct {
 	Config     *Config
 	ConfigPath string
 }
 
-// NewConfigManager создает новый менеджер конфигурации
-func NewConfigManager() (*ConfigManager, error) {
+// Path возвращает путь к конфигурационному файлу.
+// Путь можно переопределить переменной окружения DEVHELPER_CONFIG.
+func Path() (string, error) {
+	if configPath := os.Getenv(EnvPrefix + "CONFIG"); configPath != "" {
+		return configPath, nil
+	}
 	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.yaml"), nil
+}
+
+// NewConfigManager создает новый менеджер конфигурации.
+// Если конфигурационного файла нет, он создается с настройками по умолчанию.
+func NewConfigManager() (*ConfigManager, error) {
+	manager, err := LoadConfigManager()
 	if err != nil {
 		return nil, err
 	}
 
-	// Создаем директорию, если она не существует
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("не удалось создать директорию конфигурации: %w", err)
+	// Если конфигурации нет, создаем ее
+	if _, err := os.Stat(manager.ConfigPath); err != nil {
+		if err := manager.Save(); err != nil {
+			return nil, err
+		}
 	}
 
-	configPath := filepath.Join(configDir, "config.yaml")
+	return manager, nil
+}
+
+// LoadConfigManager создает менеджер конфигурации без изменения файловой системы:
+// если конфигурационного файла нет, используются настройки по умолчанию,
+// а файл создается только при сохранении.
+func LoadConfigManager() (*ConfigManager, error) {
+	configPath, err := Path()
+	if err != nil {
+		return nil, err
+	}
 
 	manager := &ConfigManager{
 		Config:     defaultConfig(),
@@ -127,11 +172,6 @@ func NewConfigManager() (*ConfigManager, error) {
 		if err := manager.Load(); err != nil {
 			return nil, err
 		}
-	} else {
-		// Если конфигурации нет, создаем ее
-		if err := manager.Save(); err != nil {
-			return nil, err
-		}
 	}
 
 	return manager, nil
@@ -139,27 +179,43 @@ func NewConfigManager() (*ConfigManager, error) {
 
 // Load загружает конфигурацию из файла
 func (m *ConfigManager) Load() error {
-	data, err := os.ReadFile(m.ConfigPath)
+	config, err := readFile(m.ConfigPath, m.Config)
+	if err != nil {
+		return err
+	}
+	m.Config = config
+	return nil
+}
+
+// readFile разбирает конфигурационный файл поверх копии base.
+// Разбор в копию гарантирует, что при ошибке исходные настройки не изменятся.
+func readFile(path string, base *Config) (*Config, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("не удалось прочитать файл конфигурации: %w", err)
+		return nil, fmt.Errorf("не удалось прочитать файл конфигурации: %w", err)
 	}
 
 	// Определяем формат файла на основе расширения
-	ext := filepath.Ext(m.ConfigPath)
+	var format string
+	ext := filepath.Ext(path)
 	switch ext {
 	case ".json":
-		if err := json.Unmarshal(data, m.Config); err != nil {
-			return fmt.Errorf("ошибка разбора JSON конфигурации: %w", err)
-		}
+		format = "json"
 	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(data, m.Config); err != nil {
-			return fmt.Errorf("ошибка разбора YAML конфигурации: %w", err)
-		}
+		format = "yaml"
 	default:
-		return fmt.Errorf("неподдерживаемое расширение файла конфигурации: %s", ext)
+		return nil, fmt.Errorf("неподдерживаемое расширение файла конфигурации: %s", ext)
 	}
 
-	return nil
+	config := *base
+	config.Profiles = make(map[string]Profile, len(base.Profiles))
+	for name, profile := range base.Profiles {
+		config.Profiles[name] = profile
+	}
+	if err := decodeStrict(path, data, format, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
 }
 
 // Save сохраняет конфигурацию в файл
@@ -184,6 +240,11 @@ func (m *ConfigManager) Save() error {
 		return fmt.Errorf("неподдерживаемое расширение файла конфигурации: %s", ext)
 	}
 
+	// Создаем директорию, если она не существует
+	if err := os.MkdirAll(filepath.Dir(m.ConfigPath), 0755); err != nil {
+		return fmt.Errorf("не удалось создать директорию конфигурации: %w", err)
+	}
+
 	if err := os.WriteFile(m.ConfigPath, data, 0644); err != nil {
 		return fmt.Errorf("не удалось записать файл конфигурации: %w", err)
 	}
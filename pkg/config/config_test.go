@@ -33,6 +33,10 @@ func TestDefaultConfig(t *testing.T) {
 
 	assert.Equal(t, 1, cfg.Monitor.DefaultInterval)
 	assert.Equal(t, "dashboard", cfg.Monitor.DefaultDisplay)
+	assert.Equal(t, 10, cfg.Monitor.LogMaxSizeMB)
+	assert.Equal(t, 24*time.Hour, cfg.Monitor.LogRotateEvery)
+	assert.Equal(t, 5, cfg.Monitor.LogMaxBackups)
+	assert.Equal(t, 7*24*time.Hour, cfg.Monitor.LogMaxAge)
 }
 
 func TestConfigManager(t *testing.T) {
@@ -200,3 +204,38 @@ func TestConfigDirPaths(t *testing.T) {
 		}
 	})
 }
+
+func TestNewConfigManager_EnvPath(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "custom", "devhelper.json")
+	t.Setenv("DEVHELPER_CONFIG", configPath)
+
+	// Файл создается по пути из окружения в формате по расширению
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, configPath, manager.ConfigPath)
+	assert.FileExists(t, configPath)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"general": {"default_indent": 8}}`), 0644))
+	manager, err = NewConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, 8, manager.Config.General.DefaultIndent)
+	assert.Equal(t, "DevHelper/1.0", manager.Config.HTTP.DefaultUserAgent)
+}
+
+func TestLoadConfigManager(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "custom", "config.yaml")
+	t.Setenv("DEVHELPER_CONFIG", configPath)
+
+	// Без файла используются настройки по умолчанию, файл не создается
+	manager, err := LoadConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfig(), manager.Config)
+	assert.NoFileExists(t, configPath)
+
+	// Файл и директория создаются при сохранении
+	manager.Config.General.DefaultIndent = 8
+	require.NoError(t, manager.Save())
+	manager, err = LoadConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, 8, manager.Config.General.DefaultIndent)
+}
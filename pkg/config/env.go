@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvPrefix префикс переменных окружения с настройками.
+// Имя переменной составляется из раздела и ключа конфигурации:
+// http.timeout задается переменной DEVHELPER_HTTP_TIMEOUT.
+const EnvPrefix = "DEVHELPER_"
+
+// EnvName возвращает имя переменной окружения для ключа вида раздел.ключ
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyEnv переопределяет настройки значениями переменных окружения DEVHELPER_*
+func (c *Config) ApplyEnv() error {
+	return c.applyEnv(os.LookupEnv)
+}
+
+// applyEnv переопределяет настройки значениями, полученными через lookup
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	for _, field := range c.fields() {
+		raw, ok := lookup(EnvName(field.key))
+		if !ok {
+			continue
+		}
+		if err := c.set(field, raw); err != nil {
+			return fmt.Errorf("неверное значение %s: %w", EnvName(field.key), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "DEVHELPER_HTTP_TIMEOUT", EnvName("http.timeout"))
+	assert.Equal(t, "DEVHELPER_MONITOR_DEFAULT_INTERVAL", EnvName("monitor.default_interval"))
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"DEVHELPER_GENERAL_COLOR_ENABLED":     "false",
+		"DEVHELPER_GENERAL_DEFAULT_INDENT":    "4",
+		"DEVHELPER_HTTP_TIMEOUT":              "5s",
+		"DEVHELPER_HTTP_DEFAULT_USER_AGENT":   "test-agent",
+		"DEVHELPER_HTTP_DEFAULT_HEADERS":      "X-A: 1, 2\nX-B: 2",
+		"DEVHELPER_GENERATOR_DEFAULT_CHARSET": "hex",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg := defaultConfig()
+	require.NoError(t, cfg.applyEnv(lookup))
+
+	assert.False(t, cfg.General.ColorEnabled)
+	assert.Equal(t, 4, cfg.General.DefaultIndent)
+	assert.Equal(t, 5*time.Second, cfg.HTTP.Timeout)
+	assert.Equal(t, "test-agent", cfg.HTTP.DefaultUserAgent)
+	assert.Equal(t, []string{"X-A: 1, 2", "X-B: 2"}, cfg.HTTP.DefaultHeaders)
+	assert.Equal(t, "hex", cfg.Generator.DefaultCharset)
+
+	// Незаданные переменные не меняют настройки
+	assert.Equal(t, 1, cfg.Monitor.DefaultInterval)
+	assert.Equal(t, "dashboard", cfg.Monitor.DefaultDisplay)
+}
+
+func TestApplyEnv_InvalidValue(t *testing.T) {
+	tests := map[string]string{
+		"DEVHELPER_HTTP_TIMEOUT":             "30",
+		"DEVHELPER_GENERAL_COLOR_ENABLED":    "maybe",
+		"DEVHELPER_MONITOR_DEFAULT_INTERVAL": "fast",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := defaultConfig()
+			err := cfg.applyEnv(func(key string) (string, bool) {
+				return value, key == name
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), name)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys возвращает ключи всех настроек вида раздел.ключ в порядке объявления
+func (c *Config) Keys() []string {
+	var keys []string
+	for _, field := range c.fields() {
+		keys = append(keys, field.key)
+	}
+	return keys
+}
+
+// Get возвращает значение настройки по ключу вида раздел.ключ в текстовом виде
+func (c *Config) Get(key string) (string, error) {
+	field, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(field.value), nil
+}
+
+// Set устанавливает значение настройки по ключу вида раздел.ключ, проверяя его тип
+func (c *Config) Set(key, raw string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	if err := c.set(field, raw); err != nil {
+		return fmt.Errorf("неверное значение %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetValues устанавливает значение настройки-списка по ключу вида раздел.ключ,
+// каждый элемент values становится элементом списка. Для остальных настроек
+// допускается только одно значение.
+func (c *Config) SetValues(key string, values []string) error {
+	if len(values) == 1 {
+		return c.Set(key, values[0])
+	}
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	if field.value.Kind() != reflect.Slice {
+		return fmt.Errorf("неверное значение %s: ожидается одно значение, получено %d", key, len(values))
+	}
+	for _, value := range values {
+		if strings.Contains(value, "\n") {
+			return fmt.Errorf("неверное значение %s: элемент списка содержит перевод строки", key)
+		}
+	}
+	return c.Set(key, strings.Join(values, "\n"))
+}
+
+// set записывает значение настройки и проверяет его допустимость.
+// Недопустимое значение не сохраняется.
+func (c *Config) set(field configField, raw string) error {
+	old := reflect.New(field.value.Type()).Elem()
+	old.Set(field.value)
+	if err := setValue(field.value, raw); err != nil {
+		return err
+	}
+	if err := c.validateKey(field.key); err != nil {
+		field.value.Set(old)
+		return err
+	}
+	return nil
+}
+
+// field находит настройку по ключу вида раздел.ключ
+func (c *Config) field(key string) (configField, error) {
+	for _, field := range c.fields() {
+		if field.key == key {
+			return field, nil
+		}
+	}
+	return configField{}, fmt.Errorf("неизвестный ключ конфигурации: %s", key)
+}
+
+// configField описывает настройку конфигурации
+type configField struct {
+	key   string        // Ключ вида раздел.ключ по тегам yaml
+	value reflect.Value // Значение поля, доступное для записи
+}
+
+// fields возвращает все настройки разделов конфигурации в порядке объявления.
+// Поля верхнего уровня, не являющиеся разделами (профили), не входят в список.
+func (c *Config) fields() []configField {
+	var fields []configField
+
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		sectionKey := yamlName(root.Type().Field(i))
+		for j := 0; j < section.NumField(); j++ {
+			fields = append(fields, configField{
+				key:   sectionKey + "." + yamlName(section.Type().Field(j)),
+				value: section.Field(j),
+			})
+		}
+	}
+	return fields
+}
+
+// yamlName возвращает имя поля из тега yaml
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
+// durationType тип time.Duration, который задается строкой вида 30s
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// formatValue возвращает значение настройки в том же виде, в котором его принимает setValue
+func formatValue(value reflect.Value) string {
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	if value.Kind() == reflect.Slice {
+		return strings.Join(value.Interface().([]string), "\n")
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// setValue записывает в поле значение, заданное строкой.
+// Элементы списков задаются с новой строки, так как запятая может входить в значение
+// заголовка; длительности - в формате 30s, 5m, 24h.
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("ожидается длительность, например 30s: %w", err)
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("ожидается true или false: %s", raw)
+		}
+		value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("ожидается целое число: %s", raw)
+		}
+		value.SetInt(int64(n))
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(raw, "\n") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("неподдерживаемый тип настройки: %s", value.Type())
+	}
+	return nil
+}
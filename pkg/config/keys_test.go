@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_GetSet(t *testing.T) {
+	cfg := defaultConfig()
+
+	require.NoError(t, cfg.Set("http.timeout", "1m30s"))
+	require.NoError(t, cfg.Set("http.follow_redirects", "false"))
+	require.NoError(t, cfg.Set("http.max_redirects", "3"))
+	require.NoError(t, cfg.Set("http.default_headers", "Accept: text/plain\nX-Debug: 1"))
+
+	assert.Equal(t, 90*time.Second, cfg.HTTP.Timeout)
+	assert.False(t, cfg.HTTP.FollowRedirects)
+	assert.Equal(t, 3, cfg.HTTP.MaxRedirects)
+	assert.Equal(t, []string{"Accept: text/plain", "X-Debug: 1"}, cfg.HTTP.DefaultHeaders)
+
+	// Get возвращает значения в виде, пригодном для Set
+	for key, expected := range map[string]string{
+		"http.timeout":             "1m30s",
+		"http.follow_redirects":    "false",
+		"http.max_redirects":       "3",
+		"http.default_headers":     "Accept: text/plain\nX-Debug: 1",
+		"general.default_format":   "json",
+		"monitor.log_rotate_every": "24h0m0s",
+	} {
+		value, err := cfg.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, value, key)
+	}
+}
+
+func TestConfig_GetSet_Errors(t *testing.T) {
+	cfg := defaultConfig()
+
+	_, err := cfg.Get("http.unknown")
+	assert.ErrorContains(t, err, "неизвестный ключ")
+	assert.ErrorContains(t, cfg.Set("http", "1"), "неизвестный ключ")
+
+	// Значение неверного типа не меняет настройку
+	assert.Error(t, cfg.Set("http.max_redirects", "many"))
+	assert.Error(t, cfg.Set("http.timeout", "30"))
+	assert.Equal(t, 10, cfg.HTTP.MaxRedirects)
+	assert.Equal(t, 30*time.Second, cfg.HTTP.Timeout)
+}
+
+func TestConfig_Keys(t *testing.T) {
+	keys := defaultConfig().Keys()
+
+	assert.Equal(t, "general.default_format", keys[0])
+	assert.Contains(t, keys, "http.timeout")
+	assert.Contains(t, keys, "monitor.log_max_age")
+}
+
+func TestConfig_SetHeaderWithComma(t *testing.T) {
+	cfg := defaultConfig()
+
+	// Запятая входит в значение заголовка и не разделяет элементы списка
+	require.NoError(t, cfg.Set("http.default_headers", "Accept: text/html, application/json\nX-Debug: 1\n"))
+	assert.Equal(t, []string{"Accept: text/html, application/json", "X-Debug: 1"}, cfg.HTTP.DefaultHeaders)
+
+	require.NoError(t, cfg.SetValues("http.default_headers", []string{"Accept: text/html, application/json", "Cache-Control: no-cache, no-store"}))
+	assert.Equal(t, []string{"Accept: text/html, application/json", "Cache-Control: no-cache, no-store"}, cfg.HTTP.DefaultHeaders)
+
+	require.NoError(t, cfg.SetValues("http.timeout", []string{"10s"}))
+	assert.Equal(t, 10*time.Second, cfg.HTTP.Timeout)
+	assert.ErrorContains(t, cfg.SetValues("http.timeout", []string{"10s", "20s"}), "ожидается одно значение, получено 2")
+	assert.ErrorContains(t, cfg.SetValues("http.default_headers", []string{"X-A: 1\nX-B: 2", "X-C: 3"}), "перевод строки")
+}
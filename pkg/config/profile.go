@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProfileEnv переменная окружения с именем профиля, переопределяющая active_profile
+const ProfileEnv = EnvPrefix + "PROFILE"
+
+// Profile содержит настройки, которые переопределяют основные при выборе профиля:
+// раздел -> ключ -> значение. Незаданные в профиле настройки не меняются.
+type Profile map[string]map[string]any
+
+// Keys возвращает ключи настроек профиля вида раздел.ключ в алфавитном порядке
+func (p Profile) Keys() []string {
+	var keys []string
+	for section, values := range p {
+		for key := range values {
+			keys = append(keys, section+"."+key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ProfileNames возвращает имена профилей в алфавитном порядке
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile переопределяет настройки значениями профиля
+func (c *Config) ApplyProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return fmt.Errorf("профиль %s не найден, профили не заданы", name)
+		}
+		return fmt.Errorf("профиль %s не найден (доступны: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	for _, key := range profile.Keys() {
+		if err := c.applyProfileKey(profile, key); err != nil {
+			return fmt.Errorf("профиль %s: %s: %w", name, key, err)
+		}
+	}
+	return nil
+}
+
+// applyProfileKey переопределяет одну настройку значением из профиля
+func (c *Config) applyProfileKey(profile Profile, key string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	section, setting, _ := strings.Cut(key, ".")
+	if err := setAny(field.value, profile[section][setting]); err != nil {
+		return err
+	}
+	return c.validateKey(key)
+}
+
+// setAny записывает в поле значение, прочитанное из YAML или JSON
+func setAny(value reflect.Value, v any) error {
+	switch x := v.(type) {
+	case []any:
+		if value.Kind() != reflect.Slice {
+			return fmt.Errorf("ожидается одно значение, получен список")
+		}
+		items := make([]string, len(x))
+		for i, item := range x {
+			items[i] = fmt.Sprint(item)
+		}
+		value.Set(reflect.ValueOf(items))
+		return nil
+	case int:
+		// Длительность в JSON задается числом наносекунд
+		if value.Type() == durationType {
+			value.SetInt(int64(x))
+			return nil
+		}
+		return setValue(value, strconv.Itoa(x))
+	case float64:
+		if value.Type() == durationType {
+			value.SetInt(int64(time.Duration(x)))
+			return nil
+		}
+		return setValue(value, strconv.FormatFloat(x, 'f', -1, 64))
+	case nil:
+		return setValue(value, "")
+	default:
+		return setValue(value, fmt.Sprint(x))
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesYAML = `
+http:
+  timeout: 30s
+  default_headers:
+    - "Accept: application/json"
+active_profile: staging
+profiles:
+  staging:
+    http:
+      timeout: 10s
+      default_headers:
+        - "X-Env: staging"
+  ci:
+    general:
+      color_enabled: false
+    monitor:
+      default_interval: 5
+`
+
+func TestLoad_Profiles(t *testing.T) {
+	manager, err := loadFile(t, "config.yaml", profilesYAML)
+	require.NoError(t, err)
+
+	cfg := manager.Config
+	assert.Equal(t, "staging", cfg.ActiveProfile)
+	assert.Equal(t, []string{"ci", "staging"}, cfg.ProfileNames())
+	assert.Equal(t, []string{"general.color_enabled", "monitor.default_interval"}, cfg.Profiles["ci"].Keys())
+
+	// Профиль не применяется при загрузке файла
+	assert.Equal(t, 30*time.Second, cfg.HTTP.Timeout)
+
+	require.NoError(t, cfg.ApplyProfile("staging"))
+	assert.Equal(t, 10*time.Second, cfg.HTTP.Timeout)
+	assert.Equal(t, []string{"X-Env: staging"}, cfg.HTTP.DefaultHeaders)
+	// Незаданные в профиле настройки не меняются
+	assert.True(t, cfg.General.ColorEnabled)
+
+	require.NoError(t, cfg.ApplyProfile("ci"))
+	assert.False(t, cfg.General.ColorEnabled)
+	assert.Equal(t, 5, cfg.Monitor.DefaultInterval)
+
+	assert.ErrorContains(t, cfg.ApplyProfile("prod"), "профиль prod не найден (доступны: ci, staging)")
+}
+
+func TestLoad_ProfilesJSON(t *testing.T) {
+	manager, err := loadFile(t, "config.json", `{
+  "profiles": {
+    "ci": {
+      "http": {"timeout": 5000000000, "max_redirects": 2, "default_headers": ["X-CI: 1"]}
+    }
+  }
+}`)
+	require.NoError(t, err)
+
+	cfg := manager.Config
+	require.NoError(t, cfg.ApplyProfile("ci"))
+	assert.Equal(t, 5*time.Second, cfg.HTTP.Timeout)
+	assert.Equal(t, 2, cfg.HTTP.MaxRedirects)
+	assert.Equal(t, []string{"X-CI: 1"}, cfg.HTTP.DefaultHeaders)
+}
+
+func TestLoad_ProfileErrors(t *testing.T) {
+	_, err := loadFile(t, "config.yaml", `
+active_profile: prod
+profiles:
+  staging:
+    http:
+      max_redirects: -1
+      timout: 10s
+      insecure_ssl: sometimes
+  ci:
+    monitor:
+      default_interval: often
+`)
+	assert.Equal(t, []string{
+		"config:2: active_profile: профиль prod не найден в разделе profiles",
+		"config:6: profiles.staging.http.max_redirects: значение -1 вне диапазона от 0 до 100",
+		"config:7: profiles.staging.http.timout: неизвестный ключ, возможно, имелся в виду profiles.staging.http.timeout",
+		"config:8: profiles.staging.http.insecure_ssl: ожидается true или false: sometimes",
+		"config:11: profiles.ci.monitor.default_interval: ожидается целое число: often",
+	}, validationErrors(t, err))
+}
+
+func TestConfig_SaveProfiles(t *testing.T) {
+	manager, err := loadFile(t, "config.yaml", profilesYAML)
+	require.NoError(t, err)
+
+	manager.Config.ActiveProfile = "ci"
+	require.NoError(t, manager.Save())
+	require.NoError(t, manager.Load())
+
+	assert.Equal(t, "ci", manager.Config.ActiveProfile)
+	assert.Equal(t, []string{"ci", "staging"}, manager.Config.ProfileNames())
+	require.NoError(t, manager.Config.ApplyProfile("staging"))
+	assert.Equal(t, 10*time.Second, manager.Config.HTTP.Timeout)
+}
+
+func TestConfig_ApplyProfileValidates(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Profiles = map[string]Profile{
+		"fast": {"monitor": {"default_interval": 0}},
+	}
+
+	assert.ErrorContains(t, cfg.ApplyProfile("fast"), "профиль fast: monitor.default_interval: значение 0 вне диапазона от 1 до 3600")
+}
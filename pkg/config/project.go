@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ProjectFileName имя конфигурационного файла проекта
+const ProjectFileName = ".devhelper.yaml"
+
+// FindProjectFile ищет конфигурационный файл проекта в каталоге dir и выше по дереву.
+// Возвращает пустую строку, если файл не найден.
+func FindProjectFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// MergeFile накладывает настройки из файла на конфигурацию: заданные в файле ключи
+// заменяют текущие значения, профили с тем же именем заменяются целиком.
+// При ошибке в файле конфигурация не меняется.
+func (c *Config) MergeFile(path string) error {
+	merged, err := readFile(path, c)
+	if err != nil {
+		return err
+	}
+	*c = *merged
+	return nil
+}
+
+// projectSensitiveKeys настройки, через которые файл проекта из чужого репозитория может
+// незаметно ослабить защиту запросов: отключить проверку сертификатов или добавить
+// заголовки ко всем запросам
+var projectSensitiveKeys = []string{"http.insecure_ssl", "http.default_headers"}
+
+// MergeProjectFile накладывает файл проекта так же, как MergeFile, и возвращает
+// чувствительные ключи, которые файл изменил, в том числе в профилях, чтобы о них
+// можно было предупредить. Отключение insecure_ssl не считается изменением.
+func (c *Config) MergeProjectFile(path string) ([]string, error) {
+	before := make(map[string]string, len(projectSensitiveKeys))
+	for _, key := range projectSensitiveKeys {
+		before[key], _ = c.Get(key)
+	}
+	profiles := c.Profiles
+
+	if err := c.MergeFile(path); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, key := range projectSensitiveKeys {
+		if value, _ := c.Get(key); value != before[key] && value != "false" {
+			changed = append(changed, key)
+		}
+	}
+	for _, name := range c.ProfileNames() {
+		profile := c.Profiles[name]
+		if reflect.DeepEqual(profile, profiles[name]) {
+			continue
+		}
+		for _, key := range projectSensitiveKeys {
+			section, setting, _ := strings.Cut(key, ".")
+			if value, ok := profile[section][setting]; ok && fmt.Sprint(value) != "false" {
+				changed = append(changed, "profiles."+name+"."+key)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// ProjectPath возвращает путь к конфигурационному файлу проекта для текущего каталога.
+// Возвращает пустую строку, если файл не найден или совпадает с пользовательским.
+func ProjectPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить текущий каталог: %w", err)
+	}
+	path, err := FindProjectFile(wd)
+	if err != nil || path == "" {
+		return "", err
+	}
+	if userPath, err := Path(); err == nil && sameFile(path, userPath) {
+		return "", nil
+	}
+	return path, nil
+}
+
+// sameFile проверяет, указывают ли пути на один и тот же файл
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "service", "cmd", "api")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	path, err := FindProjectFile(nested)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	// Файл находится в ближайшем родительском каталоге
+	rootFile := filepath.Join(root, ProjectFileName)
+	require.NoError(t, os.WriteFile(rootFile, []byte("{}\n"), 0644))
+	path, err = FindProjectFile(nested)
+	require.NoError(t, err)
+	assert.Equal(t, rootFile, path)
+
+	serviceFile := filepath.Join(root, "service", ProjectFileName)
+	require.NoError(t, os.WriteFile(serviceFile, []byte("{}\n"), 0644))
+	path, err = FindProjectFile(nested)
+	require.NoError(t, err)
+	assert.Equal(t, serviceFile, path)
+}
+
+func TestConfig_MergeFile(t *testing.T) {
+	user, err := loadFile(t, "config.yaml", `
+http:
+  timeout: 10s
+  default_user_agent: user-agent
+profiles:
+  staging:
+    http:
+      timeout: 20s
+  local:
+    http:
+      timeout: 1s
+`)
+	require.NoError(t, err)
+	cfg := user.Config
+
+	project := filepath.Join(t.TempDir(), ProjectFileName)
+	require.NoError(t, os.WriteFile(project, []byte(`
+http:
+  default_headers:
+    - "X-Team: api"
+active_profile: staging
+profiles:
+  staging:
+    http:
+      max_redirects: 3
+`), 0644))
+	require.NoError(t, cfg.MergeFile(project))
+
+	// Ключи проекта заменяют пользовательские, остальные сохраняются
+	assert.Equal(t, []string{"X-Team: api"}, cfg.HTTP.DefaultHeaders)
+	assert.Equal(t, 10*time.Second, cfg.HTTP.Timeout)
+	assert.Equal(t, "user-agent", cfg.HTTP.DefaultUserAgent)
+	assert.Equal(t, "staging", cfg.ActiveProfile)
+
+	// Профиль с тем же именем заменяется целиком, другие профили сохраняются
+	assert.Equal(t, []string{"local", "staging"}, cfg.ProfileNames())
+	assert.Equal(t, []string{"http.max_redirects"}, cfg.Profiles["staging"].Keys())
+}
+
+func TestConfig_MergeProjectFile(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.HTTP.DefaultHeaders = []string{"X-Team: api"}
+	cfg.Profiles = map[string]Profile{"local": {"http": {"insecure_ssl": true}}}
+
+	project := filepath.Join(t.TempDir(), ProjectFileName)
+	require.NoError(t, os.WriteFile(project, []byte(`
+http:
+  insecure_ssl: true
+  default_headers:
+    - "X-Team: api"
+    - "Authorization: Bearer leaked"
+profiles:
+  ci:
+    http:
+      insecure_ssl: true
+      timeout: 5s
+  safe:
+    http:
+      insecure_ssl: false
+`), 0644))
+	keys, err := cfg.MergeProjectFile(project)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.insecure_ssl", "http.default_headers", "profiles.ci.http.insecure_ssl"}, keys)
+
+	// Файл, не меняющий чувствительные настройки, не вызывает предупреждения
+	require.NoError(t, os.WriteFile(project, []byte(`
+http:
+  insecure_ssl: false
+  timeout: 5s
+`), 0644))
+	cfg = defaultConfig()
+	keys, err = cfg.MergeProjectFile(project)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Equal(t, 5*time.Second, cfg.HTTP.Timeout)
+}
+
+func TestConfig_MergeFileInvalid(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Profiles = map[string]Profile{"local": {"http": {"timeout": "1s"}}}
+
+	project := filepath.Join(t.TempDir(), ProjectFileName)
+	require.NoError(t, os.WriteFile(project, []byte(`
+general:
+  default_indent: 4
+profiles:
+  ci:
+    http:
+      timeout: -1s
+`), 0644))
+
+	err := cfg.MergeFile(project)
+	assert.ErrorContains(t, err, project+":7: profiles.ci.http.timeout")
+
+	// При ошибке конфигурация не меняется
+	assert.Equal(t, 2, cfg.General.DefaultIndent)
+	assert.Equal(t, []string{"local"}, cfg.ProfileNames())
+}
@@ -0,0 +1,455 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/styles"
+	"gopkg.in/yaml.v3"
+)
+
+// Допустимые значения перечислимых настроек
+var (
+	// Formats форматы данных (general.default_format)
+	Formats = []string{"json", "yaml", "xml"}
+	// Charsets наборы символов генератора строк (generator.default_charset)
+	Charsets = []string{"alphanumeric", "alpha", "numeric", "ascii", "hex"}
+	// OutputTypes форматы вывода генератора (generator.default_output_type)
+	OutputTypes = []string{"string", "json", "csv"}
+	// DisplayModes режимы отображения монитора (monitor.default_display)
+	DisplayModes = []string{"dashboard", "simple", "csv", "json", "ndjson"}
+)
+
+// ValidationError описывает ошибку в конфигурационном файле
+type ValidationError struct {
+	Path    string // Конфигурационный файл
+	Line    int    // Строка файла, 0 - неизвестна
+	Key     string // Ключ вида раздел.ключ, пустая строка - ошибка не относится к ключу
+	Message string
+}
+
+// Error возвращает ошибку в виде файл:строка: ключ: сообщение
+func (e *ValidationError) Error() string {
+	location := e.Path
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.Path, e.Line)
+	}
+	if e.Key != "" {
+		return fmt.Sprintf("%s: %s: %s", location, e.Key, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, e.Message)
+}
+
+// ValidationErrors содержит все ошибки конфигурационного файла в порядке строк
+type ValidationErrors []*ValidationError
+
+// Error возвращает ошибки по одной на строку
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rule проверяет значение одной настройки
+type rule struct {
+	key   string
+	check func(c *Config) error
+}
+
+// rules правила проверки настроек
+var rules = []rule{
+	{"general.default_format", func(c *Config) error { return oneOf(c.General.DefaultFormat, Formats) }},
+	{"general.default_indent", func(c *Config) error { return inRange(c.General.DefaultIndent, 0, 16) }},
+	{"http.timeout", func(c *Config) error { return notNegative(c.HTTP.Timeout) }},
+	{"http.max_redirects", func(c *Config) error { return inRange(c.HTTP.MaxRedirects, 0, 100) }},
+	{"http.default_headers", func(c *Config) error { return validHeaders(c.HTTP.DefaultHeaders) }},
+	{"formatter.json_style", func(c *Config) error { return knownStyle(c.Formatter.JSONStyle) }},
+	{"formatter.yaml_style", func(c *Config) error { return knownStyle(c.Formatter.YAMLStyle) }},
+	{"formatter.xml_style", func(c *Config) error { return knownStyle(c.Formatter.XMLStyle) }},
+	{"formatter.wrap_width", func(c *Config) error { return inRange(c.Formatter.WrapWidth, 1, 1000) }},
+	{"generator.default_charset", func(c *Config) error { return oneOf(c.Generator.DefaultCharset, Charsets) }},
+	{"generator.default_date_format", func(c *Config) error { return notEmpty(c.Generator.DefaultDateFormat) }},
+	{"generator.default_output_type", func(c *Config) error { return oneOf(c.Generator.DefaultOutputType, OutputTypes) }},
+	{"monitor.default_interval", func(c *Config) error { return inRange(c.Monitor.DefaultInterval, 1, 3600) }},
+	{"monitor.default_display", func(c *Config) error { return oneOf(c.Monitor.DefaultDisplay, DisplayModes) }},
+	{"monitor.log_max_size_mb", func(c *Config) error { return inRange(c.Monitor.LogMaxSizeMB, 0, 1<<20) }},
+	{"monitor.log_rotate_every", func(c *Config) error { return notNegative(c.Monitor.LogRotateEvery) }},
+	{"monitor.log_max_backups", func(c *Config) error { return inRange(c.Monitor.LogMaxBackups, 0, 10000) }},
+	{"monitor.log_max_age", func(c *Config) error { return notNegative(c.Monitor.LogMaxAge) }},
+}
+
+// Validate проверяет допустимость значений всех настроек
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	for _, r := range rules {
+		if err := r.check(c); err != nil {
+			errs = append(errs, &ValidationError{Key: r.key, Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateKey проверяет допустимость значения одной настройки
+func (c *Config) validateKey(key string) error {
+	for _, r := range rules {
+		if r.key == key {
+			return r.check(c)
+		}
+	}
+	return nil
+}
+
+// oneOf проверяет, что значение входит в список допустимых
+func oneOf(value string, allowed []string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("недопустимое значение %q (доступны: %s)", value, strings.Join(allowed, ", "))
+}
+
+// inRange проверяет, что число находится в диапазоне [min, max]
+func inRange(value, min, max int) error {
+	if value < min || value > max {
+		return fmt.Errorf("значение %d вне диапазона от %d до %d", value, min, max)
+	}
+	return nil
+}
+
+// notNegative проверяет, что длительность не отрицательна
+func notNegative(value time.Duration) error {
+	if value < 0 {
+		return fmt.Errorf("длительность %s не может быть отрицательной", value)
+	}
+	return nil
+}
+
+// notEmpty проверяет, что строка не пуста
+func notEmpty(value string) error {
+	if value == "" {
+		return fmt.Errorf("значение не может быть пустым")
+	}
+	return nil
+}
+
+// validHeaders проверяет, что заголовки заданы в формате "Ключ: Значение"
+func validHeaders(headers []string) error {
+	for _, header := range headers {
+		name, _, found := strings.Cut(header, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("заголовок %q должен иметь формат 'Ключ: Значение'", header)
+		}
+	}
+	return nil
+}
+
+// knownStyle проверяет, что стиль подсветки известен chroma
+func knownStyle(name string) error {
+	if _, ok := styles.Registry[name]; ok {
+		return nil
+	}
+	return fmt.Errorf("неизвестный стиль подсветки %q (например: monokai, github, dracula, solarized-dark)", name)
+}
+
+// decodeStrict разбирает конфигурационный файл, отклоняя неизвестные ключи и значения
+// неверного типа, и проверяет допустимость значений. Все найденные ошибки возвращаются
+// вместе как ValidationErrors с номерами строк.
+func decodeStrict(path string, data []byte, format string, cfg *Config) error {
+	// JSON является подмножеством YAML, поэтому строки ключей определяются одинаково
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil && format == "yaml" {
+		return ValidationErrors{lineError(path, err.Error(), nil)}
+	}
+	lines := keyLines(&root)
+
+	var errs ValidationErrors
+	for _, key := range sortedKeys(lines) {
+		if err := checkKnownKey(cfg, key); err != nil {
+			errs = append(errs, &ValidationError{Path: path, Line: lines[key], Key: key, Message: err.Error()})
+		}
+	}
+
+	var decodeErrs ValidationErrors
+	switch format {
+	case "json":
+		decodeErrs = decodeJSON(path, data, cfg, lines)
+	case "yaml":
+		decodeErrs = decodeYAML(path, data, cfg, lines)
+	}
+	errs = append(errs, decodeErrs...)
+
+	// Значения проверяются, только если файл удалось разобрать
+	if len(decodeErrs) == 0 {
+		if err := cfg.Validate(); err != nil {
+			for _, e := range err.(ValidationErrors) {
+				e.Path, e.Line = path, lines[e.Key]
+				errs = append(errs, e)
+			}
+		}
+		errs = append(errs, validateProfiles(path, cfg, lines)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return errs
+}
+
+// decodeYAML разбирает YAML и возвращает ошибки типов значений
+func decodeYAML(path string, data []byte, cfg *Config, lines map[string]int) ValidationErrors {
+	err := yaml.Unmarshal(data, cfg)
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		if err != nil {
+			return ValidationErrors{lineError(path, err.Error(), lines)}
+		}
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, message := range typeErr.Errors {
+		errs = append(errs, lineError(path, message, lines))
+	}
+	return errs
+}
+
+// decodeJSON разбирает JSON и возвращает ошибку синтаксиса или типа значения
+func decodeJSON(path string, data []byte, cfg *Config, lines map[string]int) ValidationErrors {
+	err := json.Unmarshal(data, cfg)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return ValidationErrors{{Path: path, Line: lineAt(data, syntaxErr.Offset), Message: syntaxErr.Error()}}
+	case errors.As(err, &typeErr):
+		return ValidationErrors{{
+			Path:    path,
+			Line:    lines[typeErr.Field],
+			Key:     typeErr.Field,
+			Message: fmt.Sprintf("ожидается значение типа %s, получено %s", typeErr.Type, typeErr.Value),
+		}}
+	}
+	return ValidationErrors{{Path: path, Message: err.Error()}}
+}
+
+// Сообщения об ошибках yaml.v3: "line N: ..." и "cannot unmarshal !!тип `значение` into тип"
+var (
+	yamlLinePattern      = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnmarshalPattern = regexp.MustCompile("^cannot unmarshal !!\\w+ `(.*)` into (.+)$")
+)
+
+// lineError преобразует сообщение yaml.v3 в ValidationError, определяя ключ по номеру строки
+func lineError(path, message string, lines map[string]int) *ValidationError {
+	m := yamlLinePattern.FindStringSubmatch(message)
+	if m == nil {
+		return &ValidationError{Path: path, Message: strings.TrimPrefix(message, "yaml: ")}
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	e := &ValidationError{Path: path, Line: line, Message: m[2]}
+	if u := yamlUnmarshalPattern.FindStringSubmatch(m[2]); u != nil {
+		e.Message = fmt.Sprintf("ожидается значение типа %s, получено %q", u[2], u[1])
+	}
+	for key, keyLine := range lines {
+		if keyLine == line && strings.Contains(key, ".") {
+			e.Key = key
+		}
+	}
+	return e
+}
+
+// lineAt возвращает номер строки для смещения в байтах
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return strings.Count(string(data[:offset]), "\n") + 1
+}
+
+// keyLines возвращает строки ключей файла на всех уровнях вложенности:
+// раздел, раздел.ключ, profiles.имя.раздел.ключ
+func keyLines(root *yaml.Node) map[string]int {
+	lines := make(map[string]int)
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		collectKeyLines(root.Content[0], "", lines)
+	}
+	return lines
+}
+
+// collectKeyLines добавляет строки ключей отображения с префиксом prefix
+func collectKeyLines(node *yaml.Node, prefix string, lines map[string]int) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := prefix + node.Content[i].Value
+		lines[key] = node.Content[i].Line
+		collectKeyLines(node.Content[i+1], key+".", lines)
+	}
+}
+
+// sortedKeys возвращает ключи в порядке строк файла
+func sortedKeys(lines map[string]int) []string {
+	keys := make([]string, 0, len(lines))
+	for key := range lines {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if lines[keys[i]] != lines[keys[j]] {
+			return lines[keys[i]] < lines[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// checkKnownKey проверяет, что раздел или ключ файла есть в конфигурации,
+// и предлагает похожий ключ для опечаток. Ключи профилей проверяются так же,
+// как ключи основных разделов.
+func checkKnownKey(cfg *Config, key string) error {
+	if key == "active_profile" || key == "profiles" {
+		return nil
+	}
+	prefix := ""
+	if rest, ok := strings.CutPrefix(key, "profiles."); ok {
+		name, setting, found := strings.Cut(rest, ".")
+		if !found {
+			return nil // Имя профиля
+		}
+		prefix, key = "profiles."+name+".", setting
+	}
+	// Вложенные значения (например, элементы ключа-отображения) отклоняются при разборе типов
+	if strings.Count(key, ".") > 1 {
+		return nil
+	}
+
+	known := cfg.Keys()
+	if !strings.Contains(key, ".") {
+		for _, k := range known {
+			if strings.HasPrefix(k, key+".") {
+				return nil
+			}
+		}
+		return unknownKeyError("неизвестный раздел", prefix+key, withPrefix(prefix, sections(known)))
+	}
+
+	section, _, _ := strings.Cut(key, ".")
+	var candidates []string
+	for _, k := range known {
+		if k == key {
+			return nil
+		}
+		if strings.HasPrefix(k, section+".") {
+			candidates = append(candidates, k)
+		}
+	}
+	// Неизвестный раздел уже отмечен, ключи внутри него не проверяются
+	if len(candidates) == 0 {
+		return nil
+	}
+	return unknownKeyError("неизвестный ключ", prefix+key, withPrefix(prefix, candidates))
+}
+
+// withPrefix добавляет префикс ко всем строкам
+func withPrefix(prefix string, values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = prefix + value
+	}
+	return result
+}
+
+// validateProfiles проверяет значения профилей и существование активного профиля
+func validateProfiles(path string, cfg *Config, lines map[string]int) ValidationErrors {
+	var errs ValidationErrors
+	for _, name := range cfg.ProfileNames() {
+		overlay := *cfg
+		profile := cfg.Profiles[name]
+		for _, key := range profile.Keys() {
+			// Неизвестные ключи уже отмечены при проверке имен
+			if _, err := overlay.field(key); err != nil {
+				continue
+			}
+			if err := overlay.applyProfileKey(profile, key); err != nil {
+				fullKey := "profiles." + name + "." + key
+				errs = append(errs, &ValidationError{Path: path, Line: lines[fullKey], Key: fullKey, Message: err.Error()})
+			}
+		}
+	}
+	if cfg.ActiveProfile != "" {
+		if _, ok := cfg.Profiles[cfg.ActiveProfile]; !ok {
+			errs = append(errs, &ValidationError{
+				Path:    path,
+				Line:    lines["active_profile"],
+				Key:     "active_profile",
+				Message: fmt.Sprintf("профиль %s не найден в разделе profiles", cfg.ActiveProfile),
+			})
+		}
+	}
+	return errs
+}
+
+// sections возвращает названия разделов конфигурации
+func sections(keys []string) []string {
+	var result []string
+	for _, key := range keys {
+		section, _, _ := strings.Cut(key, ".")
+		if len(result) == 0 || result[len(result)-1] != section {
+			result = append(result, section)
+		}
+	}
+	return result
+}
+
+// unknownKeyError формирует ошибку неизвестного ключа с подсказкой похожего имени
+func unknownKeyError(message, key string, candidates []string) error {
+	best, bestDistance := "", 3 // Подсказываются имена, отличающиеся не более чем на 2 символа
+	for _, candidate := range candidates {
+		if d := editDistance(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best != "" {
+		return fmt.Errorf("%s, возможно, имелся в виду %s", message, best)
+	}
+	return errors.New(message)
+}
+
+// editDistance возвращает расстояние Левенштейна между строками
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFile записывает конфигурацию во временный файл и загружает ее
+func loadFile(t *testing.T, name, content string) (*ConfigManager, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	manager := &ConfigManager{Config: defaultConfig(), ConfigPath: path}
+	return manager, manager.Load()
+}
+
+// validationErrors проверяет тип ошибки и возвращает ошибки в виде строк без пути к файлу
+func validationErrors(t *testing.T, err error) []string {
+	t.Helper()
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs), "ожидается ValidationErrors, получено %v", err)
+
+	var result []string
+	for _, e := range errs {
+		e.Path = "config"
+		result = append(result, e.Error())
+	}
+	return result
+}
+
+func TestLoad_Valid(t *testing.T) {
+	manager, err := loadFile(t, "config.yaml", `
+general:
+  default_indent: 4
+http:
+  timeout: 10s
+  default_headers:
+    - "Accept: application/json"
+formatter:
+  json_style: github
+`)
+	require.NoError(t, err)
+	assert.Equal(t, 4, manager.Config.General.DefaultIndent)
+	assert.Equal(t, 10*time.Second, manager.Config.HTTP.Timeout)
+	assert.Equal(t, "github", manager.Config.Formatter.JSONStyle)
+	// Незаданные в файле настройки сохраняют значения по умолчанию
+	assert.Equal(t, "DevHelper/1.0", manager.Config.HTTP.DefaultUserAgent)
+}
+
+func TestLoad_UnknownKeys(t *testing.T) {
+	manager, err := loadFile(t, "config.yaml", `
+http:
+  timout: 10s
+  max_redirects: 5
+htp:
+  timeout: 5s
+`)
+	assert.Equal(t, []string{
+		"config:3: http.timout: неизвестный ключ, возможно, имелся в виду http.timeout",
+		"config:5: htp: неизвестный раздел, возможно, имелся в виду http",
+	}, validationErrors(t, err))
+
+	// При ошибке настройки не меняются
+	assert.Equal(t, 10, manager.Config.HTTP.MaxRedirects)
+}
+
+func TestLoad_InvalidValues(t *testing.T) {
+	_, err := loadFile(t, "config.yaml", `
+formatter:
+  wrap_width: -5
+  json_style: nosuchstyle
+generator:
+  default_charset: cyrillic
+monitor:
+  default_interval: 0
+`)
+	assert.Equal(t, []string{
+		"config:3: formatter.wrap_width: значение -5 вне диапазона от 1 до 1000",
+		"config:4: formatter.json_style: неизвестный стиль подсветки \"nosuchstyle\" (например: monokai, github, dracula, solarized-dark)",
+		"config:6: generator.default_charset: недопустимое значение \"cyrillic\" (доступны: alphanumeric, alpha, numeric, ascii, hex)",
+		"config:8: monitor.default_interval: значение 0 вне диапазона от 1 до 3600",
+	}, validationErrors(t, err))
+}
+
+func TestLoad_TypeErrors(t *testing.T) {
+	_, err := loadFile(t, "config.yaml", `
+http:
+  timeout: 30
+  max_redirects: many
+`)
+	assert.Equal(t, []string{
+		"config:3: http.timeout: ожидается значение типа time.Duration, получено \"30\"",
+		"config:4: http.max_redirects: ожидается значение типа int, получено \"many\"",
+	}, validationErrors(t, err))
+}
+
+func TestLoad_SyntaxError(t *testing.T) {
+	_, err := loadFile(t, "config.yaml", "general:\n  default_indent: 2\n bad: [\n")
+	errs := validationErrors(t, err)
+	require.Len(t, errs, 1)
+	assert.Regexp(t, `^config:\d+: `, errs[0])
+}
+
+func TestLoad_JSON(t *testing.T) {
+	_, err := loadFile(t, "config.json", "{\n  \"http\": {\n    \"timeout\": 1000000000,\n    \"max_redirect\": 3\n  }\n}\n")
+	assert.Equal(t, []string{
+		"config:4: http.max_redirect: неизвестный ключ, возможно, имелся в виду http.max_redirects",
+	}, validationErrors(t, err))
+
+	_, err = loadFile(t, "config.json", "{\n  \"general\": {\n    \"default_indent\": \"four\"\n  }\n}\n")
+	assert.Equal(t, []string{
+		"config:3: general.default_indent: ожидается значение типа int, получено string",
+	}, validationErrors(t, err))
+
+	_, err = loadFile(t, "config.json", "{\n  \"general\": {\n    \"default_indent\": 4,\n  }\n}\n")
+	errs := validationErrors(t, err)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "config:4: ")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := defaultConfig()
+	require.NoError(t, cfg.Validate())
+
+	cfg.HTTP.DefaultHeaders = []string{"no colon"}
+	cfg.Monitor.LogMaxAge = -time.Hour
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "http.default_headers")
+	assert.Contains(t, err.Error(), "monitor.log_max_age")
+}
+
+func TestConfig_SetValidates(t *testing.T) {
+	cfg := defaultConfig()
+
+	assert.Error(t, cfg.Set("formatter.wrap_width", "-5"))
+	assert.Error(t, cfg.Set("monitor.default_display", "fancy"))
+	assert.Error(t, cfg.Set("monitor.default_interval", "0"))
+
+	// Недопустимое значение не сохраняется
+	assert.Equal(t, 80, cfg.Formatter.WrapWidth)
+	assert.Equal(t, "dashboard", cfg.Monitor.DefaultDisplay)
+}